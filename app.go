@@ -0,0 +1,370 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Starter is implemented by long-running components that have to be started after their dependencies, e.g. HTTP servers or consumers
+type Starter interface {
+	Start(ctx context.Context) error
+}
+
+// Stopper is implemented by components that have to be stopped before their dependencies
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
+
+const (
+	// DefaultStartTimeout is a time a single Start() hook is given by default
+	DefaultStartTimeout = 15 * time.Second
+	// DefaultStopTimeout is a time a single Stop() hook is given by default
+	DefaultStopTimeout = 15 * time.Second
+)
+
+// App manages lifecycle of singletons and implementations bound to a Container that implement Starter and/or Stopper.
+// Components are started in dependency order and stopped in reverse order. Dependencies are constructor arguments,
+// including named ones and collections of all bindings of a type, and fields filled by Fill() struct tags.
+// Arguments of setters and post-construct hooks are not taken into account.
+type App struct {
+	container    Container
+	resolver     Resolver
+	startTimeout time.Duration
+	stopTimeout  time.Duration
+	signals      []os.Signal
+	started      []component
+	running      bool
+	lock         sync.Mutex
+}
+
+// component is a bound instance with lifecycle hooks
+type component struct {
+	name     string
+	instance any
+}
+
+// NewApp creates an App managing components bound to provided Container
+func NewApp(c Container, opts ...Option) *App {
+	var options = newAppOptions(opts)
+
+	return &App{
+		container:    c,
+		resolver:     NewResolver(c),
+		startTimeout: options.startTimeout,
+		stopTimeout:  options.stopTimeout,
+		signals:      options.signals,
+	}
+}
+
+// Start starts components in dependency order. If any of them fails, already started components are stopped in reverse order.
+// Each Start() hook receives a context with a deadline of the start timeout.
+func (self *App) Start(ctx context.Context) error {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	if self.running {
+		return errors.New("di: app is already started")
+	}
+
+	// pending conditional bindings may provide components
+	if err := self.container.Build(); err != nil {
+		return err
+	}
+
+	var list, err = self.components()
+	if err != nil {
+		return err
+	}
+
+	for _, c := range list {
+		if starter, ok := c.instance.(Starter); ok {
+			if err = runHook(ctx, self.startTimeout, starter.Start); err != nil {
+				err = fmt.Errorf("di: unable to start %s: %w", c.name, err)
+				// ctx may be cancelled already, stop hooks are limited by the stop timeout instead
+				if rbErr := self.stop(context.Background()); rbErr != nil {
+					err = fmt.Errorf("%w (rollback: %s)", err, rbErr.Error())
+				}
+
+				return err
+			}
+		}
+
+		self.started = append(self.started, c)
+	}
+
+	self.running = true
+
+	return nil
+}
+
+// Stop stops started components in reverse order. All the components are stopped even if some of them fail,
+// the first error is returned. Each Stop() hook receives a context with a deadline of the stop timeout.
+func (self *App) Stop(ctx context.Context) error {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	self.running = false
+
+	return self.stop(ctx)
+}
+
+// Run starts the app, waits for one of the signals (SIGINT and SIGTERM by default) or context cancellation and stops the app.
+// Signals are not handled if an empty list is provided with WithSignals().
+func (self *App) Run(ctx context.Context) error {
+	var sigCtx, cancel = ctx, context.CancelFunc(func() {})
+	if len(self.signals) > 0 {
+		// signals are caught since the start, so that they don't terminate the process while components are starting
+		sigCtx, cancel = signal.NotifyContext(ctx, self.signals...)
+	}
+
+	defer cancel()
+
+	if err := self.Start(ctx); err != nil {
+		return err
+	}
+
+	<-sigCtx.Done()
+
+	// ctx may already be cancelled, stop hooks are limited by the stop timeout instead
+	return self.Stop(context.Background())
+}
+
+// stop stops started components in reverse order
+func (self *App) stop(ctx context.Context) (err error) {
+	for i := len(self.started) - 1; i >= 0; i-- {
+		var c = self.started[i]
+		if stopper, ok := c.instance.(Stopper); ok {
+			if stopErr := runHook(ctx, self.stopTimeout, stopper.Stop); stopErr != nil {
+				stopErr = fmt.Errorf("di: unable to stop %s: %w", c.name, stopErr)
+				if err == nil {
+					err = stopErr
+				} else {
+					err = fmt.Errorf("%w; %s", err, stopErr.Error())
+				}
+			}
+		}
+	}
+
+	self.started = nil
+
+	return err
+}
+
+// components lists instances implementing Starter or Stopper ordered so that dependencies go first
+func (self *App) components() ([]component, error) {
+	var (
+		infos   = self.container.Bindings()
+		index   = make(map[bindingKey]int)
+		byType  = make(map[reflect.Type][]int)
+		visited = make(map[int]bool)
+		seen    = make(map[any]bool)
+		out     []component
+		visit   func(i int) error
+	)
+
+	for i, info := range infos {
+		if info.State == StateActive {
+			index[bindingKey{info.Abstraction, info.Name}] = i
+			byType[info.Abstraction] = append(byType[info.Abstraction], i)
+		}
+	}
+
+	visit = func(i int) error {
+		if visited[i] {
+			return nil
+		}
+
+		visited[i] = true
+
+		var info = infos[i]
+		if info.State != StateActive {
+			return nil
+		}
+
+		for _, j := range self.dependencies(info, index, byType) {
+			if err := visit(j); err != nil {
+				return err
+			}
+		}
+
+		if info.Kind == KindFactory {
+			return nil
+		}
+
+		var receiver = reflect.New(info.Abstraction)
+		if err := self.resolver.Resolve(receiver.Interface(), WithName(info.Name)); err != nil {
+			return err
+		}
+
+		var instance = receiver.Elem().Interface()
+		switch instance.(type) {
+		case Starter, Stopper:
+		default:
+			return nil
+		}
+
+		// the same instance may be bound under several abstractions or names
+		if key, ok := instanceKey(instance); ok {
+			if seen[key] {
+				return nil
+			}
+
+			seen[key] = true
+		}
+
+		out = append(out, component{name: fmt.Sprintf("%s [%s]", info.Abstraction.String(), info.Name), instance: instance})
+
+		return nil
+	}
+
+	for i := range infos {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+// bindingKey identifies a binding by its abstraction and name
+type bindingKey struct {
+	abstraction reflect.Type
+	name        string
+}
+
+// dependencies lists indexes of bindings a binding depends on: constructor arguments resolved by name or collected from
+// all bindings of the element type, and fields filled by Fill() struct tags
+func (self *App) dependencies(info BindingInfo, index map[bindingKey]int, byType map[reflect.Type][]int) (out []int) {
+	var list, err = self.container.ListBindings(info.Abstraction)
+	if err != nil {
+		return nil
+	}
+
+	var bnd, ok = list[info.Name]
+	if !ok {
+		return nil
+	}
+
+	var add = func(t reflect.Type, name string) {
+		if i, ok := index[bindingKey{t, name}]; ok {
+			out = append(out, i)
+			return
+		}
+
+		if name == DefaultBindName && isCollection(t) {
+			out = append(out, byType[t.Elem()]...)
+		}
+	}
+
+	if info.Constructor != nil {
+		var names, err = argumentNames(info.Constructor, bnd.namedArgs)
+		if err != nil {
+			return out
+		}
+
+		for i := 0; i < info.Constructor.NumIn(); i++ {
+			var t = info.Constructor.In(i)
+			if isOptional(t) {
+				t = reflect.Zero(t).Interface().(optional).abstraction()
+			}
+
+			add(t, names[i])
+		}
+	}
+
+	if bnd.fill && bnd.instance != nil {
+		for _, field := range filledFields(reflect.TypeOf(bnd.instance)) {
+			add(field.abstraction, field.name)
+		}
+	}
+
+	return out
+}
+
+// filledFields lists abstractions and names of struct fields Fill() resolves by `di:"type"`, `di:"name"` and `di:"name=..."` tags
+func filledFields(t reflect.Type) []bindingKey {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var out []bindingKey
+	for i := 0; i < t.NumField(); i++ {
+		var tag, ok = t.Field(i).Tag.Lookup("di")
+		if !ok {
+			continue
+		}
+
+		switch tag = strings.TrimSuffix(tag, omitemptySuffix); {
+		case tag == "type":
+			out = append(out, bindingKey{t.Field(i).Type, DefaultBindName})
+
+		case tag == "name":
+			out = append(out, bindingKey{t.Field(i).Type, t.Field(i).Name})
+
+		case strings.HasPrefix(tag, nameTagPrefix) && tag != nameTagPrefix:
+			out = append(out, bindingKey{t.Field(i).Type, strings.TrimPrefix(tag, nameTagPrefix)})
+		}
+	}
+
+	return out
+}
+
+// instanceKey returns a map key identifying an instance: pointers, maps and channels are identified by their address,
+// other values by themselves. Values that can't be used as map keys, e.g. structs holding slices in interface fields, are not identified.
+func instanceKey(instance any) (key any, ok bool) {
+	var v = reflect.ValueOf(instance)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Chan, reflect.UnsafePointer:
+		return struct {
+			t reflect.Type
+			p uintptr
+		}{v.Type(), v.Pointer()}, true
+	}
+
+	if !v.Type().Comparable() {
+		return nil, false
+	}
+
+	defer func() {
+		if recover() != nil {
+			key, ok = nil, false
+		}
+	}()
+
+	// comparable types may still hold unhashable values in interface fields, hashing panics then
+	_ = map[any]bool{instance: true}
+
+	return instance, true
+}
+
+// runHook calls a lifecycle hook limiting its execution time
+func runHook(ctx context.Context, timeout time.Duration, hook func(context.Context) error) error {
+	if timeout <= 0 {
+		return hook(ctx)
+	}
+
+	var hookCtx, cancel = context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var done = make(chan error, 1)
+	go func() { done <- hook(hookCtx) }()
+
+	select {
+	case err := <-done:
+		return err
+
+	case <-hookCtx.Done():
+		return hookCtx.Err()
+	}
+}
@@ -0,0 +1,270 @@
+package di_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/HnH/di"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestAppSuite(t *testing.T) {
+	suite.Run(t, new(AppSuite))
+}
+
+type AppSuite struct {
+	suite.Suite
+}
+
+// lifecycleLog records lifecycle hook calls
+type lifecycleLog struct {
+	calls []string
+	lock  sync.Mutex
+}
+
+func (l *lifecycleLog) add(call string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.calls = append(l.calls, call)
+}
+
+func (l *lifecycleLog) has(call string) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	for _, c := range l.calls {
+		if c == call {
+			return true
+		}
+	}
+
+	return false
+}
+
+// component is a Starter and Stopper recording its calls
+type component struct {
+	name     string
+	log      *lifecycleLog
+	startErr error
+	block    bool
+}
+
+func (c *component) Start(ctx context.Context) error {
+	if c.block {
+		<-ctx.Done()
+	}
+
+	c.log.add("start " + c.name)
+
+	return c.startErr
+}
+
+func (c *component) Stop(ctx context.Context) error {
+	c.log.add("stop " + c.name)
+
+	return nil
+}
+
+// stopper is a Stopper only
+type stopper struct {
+	log *lifecycleLog
+}
+
+func (s stopper) Stop(ctx context.Context) error {
+	s.log.add("stop stopper")
+
+	return errors.New("stop error")
+}
+
+type (
+	server   interface{ Start(context.Context) error }
+	consumer interface{ Start(context.Context) error }
+)
+
+func (suite *AppSuite) TestStartStop() {
+	var (
+		log = new(lifecycleLog)
+		c   = di.NewContainer()
+	)
+
+	suite.Require().NoError(c.Singleton(func() server { return &component{name: "metrics", log: log} }, di.WithName("metrics")))
+	suite.Require().NoError(c.Singleton(func() *component { return &component{name: "database", log: log} }))
+	suite.Require().NoError(c.Singleton(func(db *component) server { return &component{name: "server", log: log} }))
+	suite.Require().NoError(c.Singleton(newCircle))
+
+	var app = di.NewApp(c)
+	suite.Require().NoError(app.Start(context.Background()))
+	suite.Require().EqualError(app.Start(context.Background()), "di: app is already started")
+	suite.Require().NoError(app.Stop(context.Background()))
+	suite.Require().Equal([]string{
+		"start metrics",
+		"start database",
+		"start server",
+		"stop server",
+		"stop database",
+		"stop metrics",
+	}, log.calls)
+
+	// stopped app can be started again
+	log.calls = nil
+	suite.Require().NoError(app.Start(context.Background()))
+	suite.Require().NoError(app.Stop(context.Background()))
+	suite.Require().Len(log.calls, 6)
+}
+
+func (suite *AppSuite) TestRollback() {
+	var (
+		log = new(lifecycleLog)
+		c   = di.NewContainer()
+		err = errors.New("start error")
+	)
+
+	suite.Require().NoError(c.Singleton(func() *component { return &component{name: "database", log: log} }))
+	suite.Require().NoError(c.Implementation(stopper{log: log}))
+	suite.Require().NoError(c.Singleton(func(*component) server { return &component{name: "server", log: log, startErr: err} }))
+	suite.Require().NoError(c.Singleton(func(server) consumer { return &component{name: "consumer", log: log} }))
+
+	var startErr = di.NewApp(c).Start(context.Background())
+	suite.Require().ErrorIs(startErr, err)
+	suite.Require().Equal("di: unable to start di_test.server [default]: start error (rollback: di: unable to stop di_test.stopper [default]: stop error)", startErr.Error())
+	suite.Require().Equal([]string{
+		"start database",
+		"start server",
+		"stop stopper",
+		"stop database",
+	}, log.calls)
+}
+
+// gateway is a server with a filled dependency
+type gateway struct {
+	*component
+	Cache *component `di:"name=cache"`
+}
+
+func (suite *AppSuite) TestNamedDependencies() {
+	var (
+		log = new(lifecycleLog)
+		c   = di.NewContainer()
+	)
+
+	suite.Require().NoError(c.Singleton(func() server { return &component{name: "metrics", log: log} }, di.WithName("metrics")))
+	suite.Require().NoError(c.Singleton(func() consumer { return &component{name: "queue", log: log} }, di.WithName("queue")))
+	suite.Require().NoError(c.Singleton(func() *component { return &component{name: "database", log: log} }, di.WithName("db")))
+	suite.Require().NoError(c.Singleton(func() *component { return &component{name: "cache", log: log} }, di.WithName("cache")))
+	suite.Require().NoError(c.Singleton(func(*component) server { return &component{name: "api", log: log} }, di.WithName("api"), di.WithNamedArgs(di.Key[*component]("db"))))
+	suite.Require().NoError(c.Singleton(func() server { return &gateway{component: &component{name: "gateway", log: log}} }, di.WithName("gateway"), di.WithFill()))
+	suite.Require().NoError(c.Singleton(func([]*component) consumer { return &component{name: "workers", log: log} }))
+
+	suite.Require().NoError(di.NewApp(c).Start(context.Background()))
+	suite.Require().Equal([]string{
+		"start metrics",
+		"start database",
+		"start api",
+		"start cache",
+		"start gateway",
+		"start queue",
+		"start workers",
+	}, log.calls)
+}
+
+// contextual cancels a start context and records an error of a stop context
+type contextual struct {
+	cancel  context.CancelFunc
+	stopErr error
+}
+
+func (c *contextual) Start(ctx context.Context) error {
+	if c.cancel == nil {
+		return nil
+	}
+
+	c.cancel()
+
+	return ctx.Err()
+}
+
+func (c *contextual) Stop(ctx context.Context) error {
+	c.stopErr = ctx.Err()
+
+	return c.stopErr
+}
+
+func (suite *AppSuite) TestRollbackCancelled() {
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		database    = new(contextual)
+		c           = di.NewContainer()
+	)
+
+	suite.Require().NoError(c.Implementation(database))
+	suite.Require().NoError(c.Singleton(func(*contextual) server { return &contextual{cancel: cancel} }))
+
+	var err = di.NewApp(c).Start(ctx)
+	suite.Require().ErrorIs(err, context.Canceled)
+	suite.Require().Equal("di: unable to start di_test.server [default]: context canceled", err.Error())
+	suite.Require().NoError(database.stopErr)
+}
+
+func (suite *AppSuite) TestBuild() {
+	var (
+		log = new(lifecycleLog)
+		c   = di.NewContainer()
+	)
+
+	suite.Require().NoError(c.Singleton(func() server { return &component{name: "fallback", log: log} }, di.IfMissing[consumer]()))
+
+	var app = di.NewApp(c)
+	suite.Require().NoError(app.Start(context.Background()))
+	suite.Require().NoError(app.Stop(context.Background()))
+	suite.Require().Equal([]string{"start fallback", "stop fallback"}, log.calls)
+}
+
+func (suite *AppSuite) TestTimeout() {
+	var (
+		log = new(lifecycleLog)
+		c   = di.NewContainer()
+	)
+
+	suite.Require().NoError(c.Implementation(&component{name: "blocking", log: log, block: true}))
+
+	var err = di.NewApp(c, di.WithStartTimeout(10*time.Millisecond)).Start(context.Background())
+	suite.Require().ErrorIs(err, context.DeadlineExceeded)
+	suite.Require().Contains(err.Error(), "di: unable to start *di_test.component [default]")
+}
+
+func (suite *AppSuite) TestRun() {
+	var (
+		log = new(lifecycleLog)
+		c   = di.NewContainer()
+	)
+
+	suite.Require().NoError(c.Implementation(&component{name: "server", log: log}))
+
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		done        = make(chan error)
+	)
+
+	go func() { done <- di.NewApp(c).Run(ctx) }()
+
+	suite.Require().Eventually(func() bool { return log.has("start server") }, time.Second, time.Millisecond)
+	cancel()
+	suite.Require().NoError(<-done)
+	suite.Require().Equal([]string{"start server", "stop server"}, log.calls)
+
+	log.calls = nil
+	go func() { done <- di.NewApp(c, di.WithSignals(os.Interrupt)).Run(context.Background()) }()
+
+	suite.Require().Eventually(func() bool { return log.has("start server") }, time.Second, time.Millisecond)
+
+	var p, err = os.FindProcess(os.Getpid())
+	suite.Require().NoError(err)
+	suite.Require().NoError(p.Signal(os.Interrupt))
+	suite.Require().NoError(<-done)
+	suite.Require().Equal([]string{"start server", "stop server"}, log.calls)
+}
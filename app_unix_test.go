@@ -0,0 +1,53 @@
+//go:build !windows && !plan9
+
+package di_test
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/HnH/di"
+)
+
+func (suite *AppSuite) TestRunWithoutSignals() {
+	var (
+		log = new(lifecycleLog)
+		c   = di.NewContainer()
+	)
+
+	suite.Require().NoError(c.Implementation(&component{name: "server", log: log}))
+
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		done        = make(chan error)
+	)
+
+	go func() { done <- di.NewApp(c, di.WithSignals()).Run(ctx) }()
+
+	suite.Require().Eventually(func() bool { return log.has("start server") }, time.Second, time.Millisecond)
+
+	// SIGTERM is in the default list, the test catches it itself so that the process survives
+	var caught = make(chan os.Signal, 1)
+	signal.Notify(caught, syscall.SIGTERM)
+	defer signal.Stop(caught)
+
+	var p, err = os.FindProcess(os.Getpid())
+	suite.Require().NoError(err)
+	suite.Require().NoError(p.Signal(syscall.SIGTERM))
+
+	select {
+	case <-caught:
+
+	case <-time.After(time.Second):
+		suite.FailNow("SIGTERM was not delivered")
+	}
+
+	suite.Require().Never(func() bool { return log.has("stop server") }, 50*time.Millisecond, time.Millisecond)
+
+	cancel()
+	suite.Require().NoError(<-done)
+	suite.Require().Equal([]string{"start server", "stop server"}, log.calls)
+}
@@ -0,0 +1,197 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// BindingKind describes how a binding was declared
+type BindingKind string
+
+const (
+	// KindSingleton is a binding declared with Container.Singleton()
+	KindSingleton BindingKind = "singleton"
+	// KindFactory is a binding declared with Container.Factory()
+	KindFactory BindingKind = "factory"
+	// KindImplementation is a binding declared with Container.Implementation()
+	KindImplementation BindingKind = "implementation"
+)
+
+// BindingState describes whether a binding takes part in resolution
+type BindingState string
+
+const (
+	// StateActive binding is used for resolution
+	StateActive BindingState = "active"
+	// StateInactive binding was skipped because of profiles or conditions mismatch
+	StateInactive BindingState = "inactive"
+	// StatePending binding waits for Container.Build() to evaluate its conditions
+	StatePending BindingState = "pending"
+)
+
+// BindingInfo is a read-only description of a binding
+type BindingInfo struct {
+	Container    uint64       // ID of a container binding belongs to
+	Abstraction  reflect.Type // type binding resolves
+	Name         string       // name binding is available under
+	Kind         BindingKind
+	State        BindingState
+	Instantiated bool           // whether binding holds an instance, factories are never instantiated
+	Constructor  reflect.Type   // signature of singleton constructor or factory method, nil for implementations
+	Dependencies []reflect.Type // abstractions constructor arguments are resolved to
+	Resolutions  uint64         // number of times binding was resolved
+	Caller       string         // location binding was declared at
+	Fill         bool           // whether Fill() is called on instances
+	Setters      []string       // setter methods called on a singleton instance
+	Profiles     []string       // profiles binding is active for
+	Conditions   []string       // conditions binding was declared with
+}
+
+// requirements describes profiles and conditions binding depends on
+func (self BindingInfo) requirements() string {
+	var out = make([]string, 0, len(self.Conditions)+1)
+	if len(self.Profiles) > 0 {
+		out = append(out, fmt.Sprintf("profile(s) [%s]", strings.Join(self.Profiles, ",")))
+	}
+
+	return strings.Join(append(out, self.Conditions...), ", ")
+}
+
+// info creates a BindingInfo out of a binding
+func (self Binding) info(container uint64, abstraction reflect.Type, name string, state BindingState) BindingInfo {
+	var out = BindingInfo{
+		Container:    container,
+		Abstraction:  abstraction,
+		Name:         name,
+		Kind:         self.kind(),
+		State:        state,
+		Instantiated: self.instance != nil,
+		Caller:       self.caller,
+		Fill:         self.fill,
+		Setters:      self.injected,
+		Profiles:     self.profiles,
+	}
+
+	switch {
+	case self.factory != nil:
+		out.Constructor = reflect.TypeOf(self.factory)
+
+	case self.constructor != nil:
+		out.Constructor = reflect.TypeOf(self.constructor)
+	}
+
+	if out.Constructor != nil {
+		out.Dependencies = dependencies(out.Constructor)
+	}
+
+	if self.resolutions != nil {
+		out.Resolutions = atomic.LoadUint64(self.resolutions)
+	}
+
+	for _, c := range self.conditions {
+		out.Conditions = append(out.Conditions, c.String())
+	}
+
+	return out
+}
+
+// kind returns the way binding was declared
+func (self Binding) kind() BindingKind {
+	switch {
+	case self.factory != nil:
+		return KindFactory
+
+	case self.constructor != nil:
+		return KindSingleton
+	}
+
+	return KindImplementation
+}
+
+// attach returns a copy of the binding stored in a container under provided abstraction and name with its own resolution counter
+func (self Binding) attach(container uint64, abstraction reflect.Type, name string) Binding {
+	self.container, self.abstraction, self.name = container, abstraction, name
+	self.resolutions = new(uint64)
+
+	return self
+}
+
+// event creates an observer event describing the binding
+func (self Binding) event() Event {
+	return Event{Container: self.container, Abstraction: self.abstraction, Name: self.name, Kind: self.kind(), Caller: self.caller, Setters: self.injected}
+}
+
+// resolved counts a resolution of the binding
+func (self Binding) resolved() {
+	if self.resolutions != nil {
+		atomic.AddUint64(self.resolutions, 1)
+	}
+}
+
+// dependencies lists abstractions function arguments are resolved to, Optional[T] and variadic arguments are unwrapped
+func dependencies(function reflect.Type) []reflect.Type {
+	var out = make([]reflect.Type, 0, function.NumIn())
+	for i := 0; i < function.NumIn(); i++ {
+		if isOptional(function.In(i)) {
+			out = append(out, reflect.Zero(function.In(i)).Interface().(optional).abstraction())
+			continue
+		}
+
+		if function.IsVariadic() && i == function.NumIn()-1 {
+			out = append(out, function.In(i).Elem())
+			continue
+		}
+
+		out = append(out, function.In(i))
+	}
+
+	return out
+}
+
+// bindingEntry is a binding together with its abstraction and name
+type bindingEntry struct {
+	abstraction reflect.Type
+	name        string
+	binding     Binding
+}
+
+// sortedBindings flattens bindings map in declaration order. Bindings of the same abstraction are grouped together
+// in order of the first declaration of that abstraction. Aliases of a single declaration are sorted by name.
+func sortedBindings(bindings map[reflect.Type]map[string]Binding) []bindingEntry {
+	var (
+		out   = make([]bindingEntry, 0, len(bindings))
+		first = make(map[reflect.Type]uint64, len(bindings))
+	)
+
+	for t, list := range bindings {
+		for name, bnd := range list {
+			out = append(out, bindingEntry{abstraction: t, name: name, binding: bnd})
+
+			if seq, ok := first[t]; !ok || bnd.seq < seq {
+				first[t] = bnd.seq
+			}
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		var a, b = out[i], out[j]
+
+		switch {
+		case a.abstraction != b.abstraction && first[a.abstraction] != first[b.abstraction]:
+			return first[a.abstraction] < first[b.abstraction]
+
+		case a.abstraction != b.abstraction:
+			return a.abstraction.String() < b.abstraction.String()
+
+		case a.binding.seq != b.binding.seq:
+			return a.binding.seq < b.binding.seq
+		}
+
+		return a.name < b.name
+	})
+
+	return out
+}
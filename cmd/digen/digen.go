@@ -0,0 +1,550 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// directive marks provider functions
+const directive = "//di:provide"
+
+// header marks generated files, they are skipped when a package is parsed
+const header = "// Code generated by digen. DO NOT EDIT."
+
+const diPath = "github.com/HnH/di"
+
+// provider is a function marked with the directive
+type provider struct {
+	name     string
+	pos      token.Position
+	factory  bool
+	fill     bool
+	names    []string
+	params   []types.Type
+	results  []types.Type // returned values except the trailing error
+	hasError bool
+	vars     []string // variables singleton instances are stored in
+}
+
+// dependency is a provider output a parameter is resolved with
+type dependency struct {
+	provider *provider
+	output   int
+	optional bool // parameter is di.Optional[T]
+}
+
+// generator builds wiring code for a single package
+type generator struct {
+	pkg       *types.Package
+	providers []*provider
+	outputs   map[string]dependency // default providers by fully qualified type
+	imports   map[string]string     // import aliases by package path
+	resolver  bool                  // whether generated code resolves bindings through a resolver
+}
+
+// generate parses a package in dir and returns formatted source code of a function creating a container
+func generate(dir, function string) ([]byte, error) {
+	var pkg, files, fset, err = load(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var g = &generator{
+		pkg:     pkg,
+		outputs: make(map[string]dependency),
+		imports: map[string]string{diPath: "di"},
+	}
+
+	if err = g.collect(files, fset); err != nil {
+		return nil, err
+	}
+
+	var order []*provider
+	if order, err = g.sort(); err != nil {
+		return nil, err
+	}
+
+	return g.emit(order, function)
+}
+
+// load parses and type checks non-test Go files of a package skipping previously generated ones
+func load(dir string) (*types.Package, []*ast.File, *token.FileSet, error) {
+	var bp, err = build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("digen: %w", err)
+	}
+
+	var (
+		fset  = token.NewFileSet()
+		files = make([]*ast.File, 0, len(bp.GoFiles))
+	)
+
+	for _, name := range bp.GoFiles {
+		var file *ast.File
+		if file, err = parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments); err != nil {
+			return nil, nil, nil, fmt.Errorf("digen: %w", err)
+		}
+
+		if len(file.Comments) > 0 && file.Comments[0].Pos() < file.Package && strings.HasPrefix(file.Comments[0].Text(), strings.TrimPrefix(header, "// ")) {
+			continue
+		}
+
+		files = append(files, file)
+	}
+
+	var path = bp.ImportPath
+	if path == "" || path == "." {
+		path = bp.Name
+	}
+
+	var (
+		conf = types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+		pkg  *types.Package
+	)
+
+	if pkg, err = conf.Check(path, fset, files, nil); err != nil {
+		return nil, nil, nil, fmt.Errorf("digen: %w", err)
+	}
+
+	return pkg, files, fset, nil
+}
+
+// collect finds provider functions marked with the directive in declaration order
+func (self *generator) collect(files []*ast.File, fset *token.FileSet) error {
+	sort.Slice(files, func(i, j int) bool {
+		return fset.Position(files[i].Package).Filename < fset.Position(files[j].Package).Filename
+	})
+
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			var fn, ok = decl.(*ast.FuncDecl)
+			if !ok || fn.Doc == nil {
+				continue
+			}
+
+			for _, c := range fn.Doc.List {
+				if c.Text != directive && !strings.HasPrefix(c.Text, directive+" ") {
+					continue
+				}
+
+				var p, err = self.provider(fn, fset.Position(fn.Pos()), strings.Fields(strings.TrimPrefix(c.Text, directive)))
+				if err != nil {
+					return err
+				}
+
+				self.providers = append(self.providers, p)
+			}
+		}
+	}
+
+	if len(self.providers) == 0 {
+		return errors.New("digen: no providers found, mark functions with " + directive)
+	}
+
+	return nil
+}
+
+// provider describes a marked function
+func (self *generator) provider(fn *ast.FuncDecl, pos token.Position, args []string) (*provider, error) {
+	if fn.Recv != nil || fn.Type.TypeParams != nil {
+		return nil, fmt.Errorf("digen: %s at %s: only non-generic functions can be providers", fn.Name.Name, pos)
+	}
+
+	var (
+		p   = &provider{name: fn.Name.Name, pos: pos}
+		sig = self.pkg.Scope().Lookup(fn.Name.Name).Type().(*types.Signature)
+	)
+
+	for _, arg := range args {
+		switch {
+		case arg == "factory":
+			p.factory = true
+
+		case arg == "fill":
+			p.fill = true
+
+		case strings.HasPrefix(arg, "name="):
+			p.names = strings.Split(strings.TrimPrefix(arg, "name="), ",")
+
+		default:
+			return nil, fmt.Errorf("digen: %s at %s: unknown directive argument %q", p.name, pos, arg)
+		}
+	}
+
+	if sig.Variadic() {
+		return nil, fmt.Errorf("digen: %s at %s: variadic providers are not supported", p.name, pos)
+	}
+
+	for i := 0; i < sig.Params().Len(); i++ {
+		p.params = append(p.params, sig.Params().At(i).Type())
+	}
+
+	for i := 0; i < sig.Results().Len(); i++ {
+		var t = sig.Results().At(i).Type()
+		if i == sig.Results().Len()-1 && types.Identical(t, types.Universe.Lookup("error").Type()) {
+			p.hasError = true
+			continue
+		}
+
+		p.results = append(p.results, t)
+	}
+
+	switch {
+	case len(p.results) == 0:
+		return nil, fmt.Errorf("digen: %s at %s: the constructor must return useful values", p.name, pos)
+
+	case p.factory && len(p.results) > 1:
+		return nil, fmt.Errorf("digen: %s at %s: factory resolvers must return exactly one value and optionally one error", p.name, pos)
+
+	case !p.factory && len(p.results) > 1 && len(p.names) > 1 && len(p.results) != len(p.names):
+		return nil, fmt.Errorf("digen: %s at %s: the constructor that returns multiple values must be called with either one name or number of names equal to number of values", p.name, pos)
+	}
+
+	for i, t := range p.results {
+		if !p.providesDefault(i) {
+			continue
+		}
+
+		var key = types.TypeString(t, nil)
+		if dep, ok := self.outputs[key]; ok {
+			return nil, fmt.Errorf("digen: %s is provided by both %s and %s", key, dep.provider.name, p.name)
+		}
+
+		self.outputs[key] = dependency{provider: p, output: i}
+	}
+
+	return p, nil
+}
+
+// providesDefault checks whether a returned value is bound with the default name, mirroring Container naming rules
+func (self *provider) providesDefault(output int) bool {
+	switch {
+	case len(self.names) == 0:
+		return true
+
+	// factories and constructors returning multiple values with a single name are bound under the first name only
+	case self.factory || len(self.results) > 1 && len(self.names) == 1:
+		return self.names[0] == "default"
+
+	case len(self.results) > 1:
+		return self.names[output] == "default"
+	}
+
+	// a single value of a singleton is bound under all names
+	for _, name := range self.names {
+		if name == "default" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dependency finds a provider a parameter is resolved with
+func (self *generator) dependency(p *provider, param types.Type) (dependency, error) {
+	var (
+		t        = param
+		optional bool
+	)
+
+	if inner, ok := optionalOf(param); ok {
+		t, optional = inner, true
+	}
+
+	var dep, ok = self.outputs[types.TypeString(t, nil)]
+	if !ok && !optional {
+		return dep, fmt.Errorf("digen: missing provider for %s required by %s at %s", types.TypeString(t, nil), p.name, p.pos)
+	}
+
+	dep.optional = optional
+
+	return dep, nil
+}
+
+// optionalOf returns T if provided type is di.Optional[T]
+func optionalOf(t types.Type) (types.Type, bool) {
+	var named, ok = t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != diPath || named.Obj().Name() != "Optional" || named.TypeArgs().Len() != 1 {
+		return nil, false
+	}
+
+	return named.TypeArgs().At(0), true
+}
+
+// sort orders providers so that dependencies go first keeping declaration order where possible
+func (self *generator) sort() ([]*provider, error) {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	var (
+		out   = make([]*provider, 0, len(self.providers))
+		state = make(map[*provider]int)
+		stack []string
+		visit func(p *provider) error
+	)
+
+	visit = func(p *provider) error {
+		switch state[p] {
+		case visited:
+			return nil
+
+		case visiting:
+			var from = 0
+			for stack[from] != p.name {
+				from++
+			}
+
+			return fmt.Errorf("digen: dependency cycle: %s -> %s", strings.Join(stack[from:], " -> "), p.name)
+		}
+
+		state[p] = visiting
+		stack = append(stack, p.name)
+
+		for _, param := range p.params {
+			var dep, err = self.dependency(p, param)
+			if err != nil {
+				return err
+			}
+
+			if dep.provider != nil {
+				if err = visit(dep.provider); err != nil {
+					return err
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[p] = visited
+		out = append(out, p)
+
+		return nil
+	}
+
+	for _, p := range self.providers {
+		if err := visit(p); err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+// qualifier returns a package alias used in generated code and registers an import
+func (self *generator) qualifier(pkg *types.Package) string {
+	if pkg == self.pkg {
+		return ""
+	}
+
+	if alias, ok := self.imports[pkg.Path()]; ok {
+		return alias
+	}
+
+	var alias = pkg.Name()
+	for i := 2; self.aliasTaken(alias); i++ {
+		alias = pkg.Name() + strconv.Itoa(i)
+	}
+
+	self.imports[pkg.Path()] = alias
+
+	return alias
+}
+
+func (self *generator) aliasTaken(alias string) bool {
+	for _, a := range self.imports {
+		if a == alias {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (self *generator) typeString(t types.Type) string {
+	return types.TypeString(t, self.qualifier)
+}
+
+// emit renders wiring code for providers in provided order
+func (self *generator) emit(order []*provider, function string) ([]byte, error) {
+	var (
+		body bytes.Buffer
+		seq  int
+	)
+
+	for _, p := range order {
+		fmt.Fprintf(&body, "\n// %s declared at %s:%d\n", p.name, filepath.Base(p.pos.Filename), p.pos.Line)
+
+		if p.factory {
+			self.emitFactory(&body, p, &seq)
+			continue
+		}
+
+		self.emitSingleton(&body, p, &seq)
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "%s\n\npackage %s\n\nimport (\n", header, self.pkg.Name())
+
+	var paths = make([]string, 0, len(self.imports))
+	for path := range self.imports {
+		paths = append(paths, path)
+	}
+
+	// standard library goes first
+	sort.Slice(paths, func(i, j int) bool {
+		if std := isStd(paths[i]); std != isStd(paths[j]) {
+			return std
+		}
+
+		return paths[i] < paths[j]
+	})
+
+	for i, path := range paths {
+		if i > 0 && isStd(paths[i-1]) && !isStd(path) {
+			out.WriteString("\n")
+		}
+
+		if alias := self.imports[path]; alias != filepath.Base(path) {
+			fmt.Fprintf(&out, "\t%s %q\n", alias, path)
+			continue
+		}
+
+		fmt.Fprintf(&out, "\t%q\n", path)
+	}
+
+	fmt.Fprintf(&out, ")\n\n// %s creates a container with bindings of functions marked with %s directive bound in dependency order\n", function, directive)
+	fmt.Fprintf(&out, "func %s(opts ...di.Option) (di.Container, error) {\n\tvar c = di.NewContainer(opts...)\n\tvar err error\n", function)
+
+	if self.resolver {
+		out.WriteString("\tvar r = di.NewResolver(c)\n")
+	}
+
+	out.Write(body.Bytes())
+	out.WriteString("\n\treturn c, nil\n}\n")
+
+	var src, err = format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("digen: unable to format generated code: %w", err)
+	}
+
+	return src, nil
+}
+
+// isStd checks whether an import path belongs to the standard library
+func isStd(path string) bool {
+	return !strings.Contains(strings.SplitN(path, "/", 2)[0], ".")
+}
+
+// emitArguments writes resolution of factory dependencies and returns argument expressions of a provider call
+func (self *generator) emitArguments(w *bytes.Buffer, p *provider, seq *int, onError string) []string {
+	var args = make([]string, len(p.params))
+	for i, param := range p.params {
+		var dep, _ = self.dependency(p, param)
+
+		var value string
+		switch {
+		case dep.provider == nil:
+			args[i] = self.typeString(param) + "{}"
+			continue
+
+		case dep.provider.factory:
+			*seq++
+			self.resolver = true
+			value = fmt.Sprintf("d%d", *seq)
+			fmt.Fprintf(w, "var %s %s\nif err = r.Resolve(&%s); err != nil {\n%s\n}\n", value, self.typeString(dep.provider.results[0]), value, onError)
+
+		default:
+			value = dep.provider.vars[dep.output]
+		}
+
+		if dep.optional {
+			value = fmt.Sprintf("%s{Value: %s, Present: true}", self.typeString(param), value)
+		}
+
+		args[i] = value
+	}
+
+	return args
+}
+
+// emitSingleton calls a provider and binds returned instances as singletons
+func (self *generator) emitSingleton(w *bytes.Buffer, p *provider, seq *int) {
+	var args = self.emitArguments(w, p, seq, "return nil, err")
+
+	var (
+		lhs  = make([]string, 0, len(p.results)+1)
+		outs = make([]string, 0, len(p.results))
+	)
+
+	for _, t := range p.results {
+		*seq++
+		p.vars = append(p.vars, fmt.Sprintf("v%d", *seq))
+		outs = append(outs, self.typeString(t))
+	}
+
+	lhs = append(lhs, p.vars...)
+	if p.hasError {
+		lhs = append(lhs, "err")
+	}
+
+	fmt.Fprintf(w, "%s := %s(%s)\n", strings.Join(lhs, ", "), p.name, strings.Join(args, ", "))
+	if p.hasError {
+		w.WriteString("if err != nil {\nreturn nil, err\n}\n")
+	}
+
+	var results = strings.Join(outs, ", ")
+	if len(outs) > 1 {
+		results = "(" + results + ")"
+	}
+
+	fmt.Fprintf(w, "if err = c.Singleton(func() %s { return %s }%s); err != nil {\nreturn nil, err\n}\n", results, strings.Join(p.vars, ", "), self.options(p))
+}
+
+// emitFactory binds a closure calling a provider as a factory method
+func (self *generator) emitFactory(w *bytes.Buffer, p *provider, seq *int) {
+	var inner bytes.Buffer
+	var args = self.emitArguments(&inner, p, seq, "return")
+
+	fmt.Fprintf(w, "if err = c.Factory(func() (out %s, err error) {\n", self.typeString(p.results[0]))
+	w.Write(inner.Bytes())
+
+	if p.hasError {
+		fmt.Fprintf(w, "return %s(%s)\n", p.name, strings.Join(args, ", "))
+	} else {
+		fmt.Fprintf(w, "return %s(%s), nil\n", p.name, strings.Join(args, ", "))
+	}
+
+	fmt.Fprintf(w, "}%s); err != nil {\nreturn nil, err\n}\n", self.options(p))
+}
+
+// options renders binding options of a provider
+func (self *generator) options(p *provider) string {
+	var out string
+	if len(p.names) > 0 {
+		var names = make([]string, len(p.names))
+		for i, name := range p.names {
+			names[i] = strconv.Quote(name)
+		}
+
+		out += fmt.Sprintf(", di.WithName(%s)", strings.Join(names, ", "))
+	}
+
+	if p.fill {
+		out += ", di.WithFill()"
+	}
+
+	return out
+}
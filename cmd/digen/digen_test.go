@@ -0,0 +1,63 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+func TestDigenSuite(t *testing.T) {
+	suite.Run(t, new(DigenSuite))
+}
+
+type DigenSuite struct {
+	suite.Suite
+}
+
+func (suite *DigenSuite) TestGenerate() {
+	var dir = filepath.Join("internal", "example")
+
+	var src, err = generate(dir, "NewContainer")
+	suite.Require().NoError(err)
+
+	var expected []byte
+	expected, err = os.ReadFile(filepath.Join(dir, "di_gen.go"))
+	suite.Require().NoError(err)
+	suite.Require().Equal(string(expected), string(src), "generated code is outdated, run go generate ./...")
+}
+
+func (suite *DigenSuite) TestGenerateFunction() {
+	var src, err = generate(filepath.Join("internal", "example"), "Wire")
+	suite.Require().NoError(err)
+	suite.Require().Contains(string(src), "func Wire(opts ...di.Option) (di.Container, error) {")
+}
+
+func (suite *DigenSuite) TestErrors() {
+	for dir, expected := range map[string]string{
+		"missing":   "digen: missing provider for *missing.Config required by newService at ",
+		"cycle":     "digen: dependency cycle: newA -> newB -> newA",
+		"duplicate": "digen: *duplicate.Config is provided by both newConfig and newDefaultConfig",
+		"directive": `digen: newConfig at `,
+		"none":      "digen: no providers found, mark functions with //di:provide",
+		"factory":   "digen: newPair at ",
+		"named":     "digen: missing provider for *named.Config required by newService at ",
+	} {
+		var _, err = generate(filepath.Join("testdata", dir), "NewContainer")
+		suite.Require().Error(err, dir)
+		suite.Require().True(strings.HasPrefix(err.Error(), expected), "%s: %s", dir, err.Error())
+	}
+}
+
+func (suite *DigenSuite) TestErrorDetails() {
+	var _, err = generate(filepath.Join("testdata", "missing"), "NewContainer")
+	suite.Require().Contains(err.Error(), "missing.go:8")
+
+	_, err = generate(filepath.Join("testdata", "directive"), "NewContainer")
+	suite.Require().Contains(err.Error(), `unknown directive argument "singleton"`)
+
+	_, err = generate(filepath.Join("testdata", "factory"), "NewContainer")
+	suite.Require().Contains(err.Error(), "factory resolvers must return exactly one value and optionally one error")
+}
@@ -0,0 +1,47 @@
+// Code generated by digen. DO NOT EDIT.
+
+package example
+
+import (
+	"context"
+
+	"github.com/HnH/di"
+)
+
+// NewContainer creates a container with bindings of functions marked with //di:provide directive bound in dependency order
+func NewContainer(opts ...di.Option) (di.Container, error) {
+	var c = di.NewContainer(opts...)
+	var err error
+	var r = di.NewResolver(c)
+
+	// newConfig declared at example.go:65
+	v1, v2 := newConfig()
+	if err = c.Singleton(func() (*Config, context.Context) { return v1, v2 }); err != nil {
+		return nil, err
+	}
+
+	// newDatabase declared at example.go:51
+	if err = c.Factory(func() (out Database, err error) {
+		return newDatabase(v1)
+	}); err != nil {
+		return nil, err
+	}
+
+	// newService declared at example.go:60
+	var d3 Database
+	if err = r.Resolve(&d3); err != nil {
+		return nil, err
+	}
+	v4 := newService(v1, d3, di.Optional[Logger]{})
+	if err = c.Singleton(func() *Service { return v4 }, di.WithFill()); err != nil {
+		return nil, err
+	}
+
+	// newReplica declared at example.go:70
+	v5 := newReplica(v1)
+	if err = c.Singleton(func() Database { return v5 }, di.WithName("replica")); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
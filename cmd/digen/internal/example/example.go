@@ -0,0 +1,72 @@
+// Package example demonstrates wiring code generated by digen
+package example
+
+import (
+	"context"
+	"errors"
+
+	"github.com/HnH/di"
+)
+
+//go:generate go run github.com/HnH/di/cmd/digen .
+
+type Config struct {
+	DSN string
+}
+
+type Database interface {
+	DSN() string
+}
+
+type Logger interface {
+	Log(string)
+}
+
+type Service struct {
+	Config   *Config
+	Database Database
+	Logger   di.Optional[Logger]
+	Primary  Database `di:"type"`
+}
+
+type database struct {
+	dsn         string
+	constructed bool
+}
+
+func (self *database) DSN() string { return self.dsn }
+
+func (self *database) Construct(context.Context) error {
+	self.constructed = true
+	return nil
+}
+
+// Constructed reports whether Construct() was called on a database
+func Constructed(db Database) bool {
+	var d, ok = db.(*database)
+	return ok && d.constructed
+}
+
+//di:provide factory
+func newDatabase(cfg *Config) (Database, error) {
+	if cfg.DSN == "" {
+		return nil, errors.New("example: empty dsn")
+	}
+
+	return &database{dsn: cfg.DSN}, nil
+}
+
+//di:provide fill
+func newService(cfg *Config, db Database, logger di.Optional[Logger]) *Service {
+	return &Service{Config: cfg, Database: db, Logger: logger}
+}
+
+//di:provide
+func newConfig() (*Config, context.Context) {
+	return &Config{DSN: "mysql://localhost"}, context.Background()
+}
+
+//di:provide name=replica
+func newReplica(cfg *Config) Database {
+	return &database{dsn: cfg.DSN + "?replica"}
+}
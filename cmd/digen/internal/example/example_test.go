@@ -0,0 +1,39 @@
+package example_test
+
+import (
+	"testing"
+
+	"github.com/HnH/di"
+	"github.com/HnH/di/cmd/digen/internal/example"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestExampleSuite(t *testing.T) {
+	suite.Run(t, new(ExampleSuite))
+}
+
+type ExampleSuite struct {
+	suite.Suite
+}
+
+func (suite *ExampleSuite) TestNewContainer() {
+	var c, err = example.NewContainer()
+	suite.Require().NoError(err)
+
+	var (
+		r   = di.NewResolver(c)
+		svc *example.Service
+	)
+
+	suite.Require().NoError(r.Resolve(&svc))
+	suite.Require().Equal("mysql://localhost", svc.Config.DSN)
+	suite.Require().True(example.Constructed(svc.Database))
+	suite.Require().True(example.Constructed(svc.Primary))
+	suite.Require().NotSame(svc.Database, svc.Primary)
+	suite.Require().False(svc.Logger.Present)
+
+	var replica example.Database
+	suite.Require().NoError(r.Resolve(&replica, di.WithName("replica")))
+	suite.Require().Equal("mysql://localhost?replica", replica.DSN())
+	suite.Require().True(example.Constructed(replica))
+}
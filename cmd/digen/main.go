@@ -0,0 +1,53 @@
+// Command digen generates wiring code for functions marked with `//di:provide` directive.
+//
+// Providers are resolved statically: generation fails if a dependency has no provider or dependencies are cyclic.
+// Generated function calls providers directly in dependency order and binds returned values to a new container,
+// factories are bound as closures. Directive accepts `factory`, `fill` and `name=a,b` arguments:
+//
+//	//di:provide
+//	func newConfig() (*Config, error) { ... }
+//
+//	//di:provide factory name=primary
+//	func newDatabase(cfg *Config) Database { ... }
+//
+// Usage:
+//
+//	//go:generate go run github.com/HnH/di/cmd/digen -o di_gen.go -func NewContainer .
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	var (
+		output   = flag.String("o", "di_gen.go", "output file name, relative to the package directory")
+		function = flag.String("func", "NewContainer", "name of the generated function")
+	)
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: digen [flags] [package directory]\n")
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	var dir = "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	var src, err = generate(dir, *function)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if err = os.WriteFile(filepath.Join(dir, *output), src, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,16 @@
+package cycle
+
+type A struct{}
+
+type B struct{}
+
+type C struct{}
+
+//di:provide
+func newC() *C { return &C{} }
+
+//di:provide
+func newA(*B, *C) *A { return &A{} }
+
+//di:provide factory
+func newB(*A) *B { return &B{} }
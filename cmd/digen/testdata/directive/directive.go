@@ -0,0 +1,6 @@
+package directive
+
+type Config struct{}
+
+//di:provide singleton
+func newConfig() *Config { return &Config{} }
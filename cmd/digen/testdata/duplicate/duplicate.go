@@ -0,0 +1,12 @@
+package duplicate
+
+type Config struct{}
+
+//di:provide
+func newConfig() *Config { return &Config{} }
+
+//di:provide name=other
+func newOtherConfig() *Config { return &Config{} }
+
+//di:provide name=other,default
+func newDefaultConfig() *Config { return &Config{} }
@@ -0,0 +1,8 @@
+package factory
+
+type A struct{}
+
+type B struct{}
+
+//di:provide factory
+func newPair() (*A, *B, error) { return &A{}, &B{}, nil }
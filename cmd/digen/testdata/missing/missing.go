@@ -0,0 +1,8 @@
+package missing
+
+type Config struct{}
+
+type Service struct{}
+
+//di:provide
+func newService(*Config) *Service { return &Service{} }
@@ -0,0 +1,13 @@
+package named
+
+type Config struct{}
+
+type Service struct{}
+
+// factories are bound under the first name only, so the default one is not provided
+//
+//di:provide factory name=primary,default
+func newConfig() *Config { return &Config{} }
+
+//di:provide
+func newService(*Config) *Service { return &Service{} }
@@ -0,0 +1,6 @@
+package none
+
+type Config struct{}
+
+// di:provide is not a directive
+func newConfig() *Config { return &Config{} }
@@ -0,0 +1,357 @@
+package di
+
+import (
+	"encoding"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConfigSource is an abstraction of an entity that provides raw configuration values by their keys.
+// Keys are dot separated paths, e.g. `db.dsn` or `http.port`.
+type ConfigSource interface {
+	Lookup(key string) (string, bool)
+}
+
+// ConfigError is returned from Fill when one or more required configuration keys were not found in any source
+type ConfigError struct {
+	Keys []string
+}
+
+// Error implements error interface
+func (self *ConfigError) Error() string {
+	return fmt.Sprintf("di: missing required config key(s): %s", strings.Join(self.Keys, ", "))
+}
+
+// Config combines several sources into one. Sources are queried in provided order and the first found value wins.
+// Returned ConfigSource should be bound into a container to be used for `di:"config=..."` struct tags:
+//
+//	err = di.Singleton(ctx, func() di.ConfigSource {
+//		return di.Config(di.FlagSource(flag.CommandLine), di.EnvSource("APP"), jsonSource)
+//	})
+func Config(sources ...ConfigSource) ConfigSource {
+	return configSources(sources)
+}
+
+type configSources []ConfigSource
+
+// Lookup implements ConfigSource interface
+func (self configSources) Lookup(key string) (string, bool) {
+	for _, src := range self {
+		if val, ok := src.Lookup(key); ok {
+			return val, true
+		}
+	}
+
+	return "", false
+}
+
+// MapSource returns a ConfigSource backed by an in-memory map
+func MapSource(values map[string]string) ConfigSource {
+	return mapSource(values)
+}
+
+type mapSource map[string]string
+
+// Lookup implements ConfigSource interface
+func (self mapSource) Lookup(key string) (val string, ok bool) {
+	val, ok = self[key]
+	return
+}
+
+// EnvSource returns a ConfigSource that reads environment variables.
+// Key is converted to an upper case variable name with dots and dashes replaced by underscores and prefixed
+// with provided prefix if it's not empty, e.g. `db.dsn` with prefix `APP` becomes `APP_DB_DSN`.
+func EnvSource(prefix string) ConfigSource {
+	return envSource(prefix)
+}
+
+type envSource string
+
+// Lookup implements ConfigSource interface
+func (self envSource) Lookup(key string) (string, bool) {
+	return os.LookupEnv(envName(string(self), key))
+}
+
+func envName(prefix, key string) string {
+	var name = strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+	if prefix != "" {
+		name = strings.ToUpper(prefix) + "_" + name
+	}
+
+	return name
+}
+
+// FlagSource returns a ConfigSource that reads flags from provided set by their names.
+// Only flags that were explicitly set are found, so that flag defaults do not shadow other sources.
+func FlagSource(fs *flag.FlagSet) ConfigSource {
+	return &flagSource{fs: fs}
+}
+
+type flagSource struct {
+	fs *flag.FlagSet
+}
+
+// Lookup implements ConfigSource interface
+func (self *flagSource) Lookup(key string) (val string, ok bool) {
+	self.fs.Visit(func(f *flag.Flag) {
+		if f.Name == key {
+			val, ok = f.Value.String(), true
+		}
+	})
+
+	return
+}
+
+// JSONSource returns a ConfigSource that reads JSON document from provided reader.
+// Nested objects are flattened into dot separated keys, arrays of scalar values are joined with commas.
+func JSONSource(r io.Reader) (ConfigSource, error) {
+	var (
+		doc map[string]any
+		dec = json.NewDecoder(r)
+	)
+
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("di: invalid json config: %w", err)
+	}
+
+	var out = make(mapSource)
+	flattenJSON(out, "", doc)
+
+	return out, nil
+}
+
+// JSONFileSource returns a ConfigSource that reads JSON document from a file
+func JSONFileSource(path string) (ConfigSource, error) {
+	var f, err = os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("di: unable to open json config: %w", err)
+	}
+
+	defer f.Close()
+
+	return JSONSource(f)
+}
+
+func flattenJSON(out mapSource, prefix string, value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, item := range v {
+			flattenJSON(out, joinConfigKey(prefix, k), item)
+		}
+
+	case []any:
+		var scalars = make([]string, 0, len(v))
+		for i, item := range v {
+			switch item.(type) {
+			case map[string]any, []any:
+				flattenJSON(out, joinConfigKey(prefix, strconv.Itoa(i)), item)
+
+			default:
+				scalars = append(scalars, fmt.Sprint(item))
+			}
+		}
+
+		if len(scalars) == len(v) {
+			out[prefix] = strings.Join(scalars, ",")
+		}
+
+	case nil:
+
+	default:
+		out[prefix] = fmt.Sprint(v)
+	}
+}
+
+func joinConfigKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return prefix + "." + key
+}
+
+const (
+	configTagPrefix  = "config="
+	configTagDefault = "default="
+)
+
+// configTag is a parsed representation of `di:"config=key,default=value"` tag.
+// Default value must be the last tag option as it may contain commas itself.
+type configTag struct {
+	key        string
+	def        string
+	hasDefault bool
+}
+
+func parseConfigTag(tag string) (out configTag, ok bool) {
+	if !strings.HasPrefix(tag, configTagPrefix) {
+		return out, false
+	}
+
+	tag = strings.TrimPrefix(tag, configTagPrefix)
+
+	var idx = strings.Index(tag, ","+configTagDefault)
+	if idx == -1 {
+		out.key = tag
+		return out, out.key != ""
+	}
+
+	out.key, out.def, out.hasDefault = tag[:idx], tag[idx+len(configTagDefault)+1:], true
+
+	return out, out.key != ""
+}
+
+var (
+	configSourceType    = reflect.TypeOf((*ConfigSource)(nil)).Elem()
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// convertConfigValue converts raw configuration value to a value of provided type
+func convertConfigValue(raw string, t reflect.Type) (out reflect.Value, err error) {
+	out = reflect.New(t).Elem()
+
+	if reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		return out, out.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+
+	if t == durationType {
+		var d time.Duration
+		if d, err = time.ParseDuration(raw); err == nil {
+			out.SetInt(int64(d))
+		}
+
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		out.SetString(raw)
+
+	case reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(raw); err == nil {
+			out.SetBool(b)
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		if i, err = strconv.ParseInt(raw, 10, t.Bits()); err == nil {
+			out.SetInt(i)
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		if u, err = strconv.ParseUint(raw, 10, t.Bits()); err == nil {
+			out.SetUint(u)
+		}
+
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, err = strconv.ParseFloat(raw, t.Bits()); err == nil {
+			out.SetFloat(f)
+		}
+
+	case reflect.Slice:
+		var parts []string
+		if raw != "" {
+			parts = strings.Split(raw, ",")
+		}
+
+		out = reflect.MakeSlice(t, len(parts), len(parts))
+		for i, part := range parts {
+			var item reflect.Value
+			if item, err = convertConfigValue(strings.TrimSpace(part), t.Elem()); err != nil {
+				return
+			}
+
+			out.Index(i).Set(item)
+		}
+
+	default:
+		err = fmt.Errorf("unsupported type %s", t.String())
+	}
+
+	return
+}
+
+// configField is a struct field that has to be filled with a configuration value
+type configField struct {
+	index     int
+	tag       configTag
+	skippable bool
+}
+
+// fillConfig resolves values for config tagged fields from a bound ConfigSource.
+// If no source is bound, fields that have defaults or can be skipped are still filled.
+// Missing required keys are aggregated into a single ConfigError.
+func (self *resolver) fillConfig(elem reflect.Value, fields []configField) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	var src ConfigSource
+	if _, err := self.getBinding(configSourceType, DefaultBindName); err == nil || requiresConfig(fields) {
+		if err = self.Resolve(&src); err != nil {
+			return err
+		}
+	}
+
+	var missing []string
+	for _, field := range fields {
+		var (
+			raw string
+			has bool
+		)
+
+		if src != nil {
+			raw, has = src.Lookup(field.tag.key)
+		}
+
+		if !has {
+			switch {
+			case field.tag.hasDefault:
+				raw = field.tag.def
+
+			case field.skippable:
+				continue
+
+			default:
+				missing = append(missing, field.tag.key)
+				continue
+			}
+		}
+
+		var val, err = convertConfigValue(raw, elem.Field(field.index).Type())
+		if err != nil {
+			return fmt.Errorf("di: invalid value for config key %s: %w", field.tag.key, err)
+		}
+
+		setField(elem, field.index, val)
+	}
+
+	if len(missing) > 0 {
+		return &ConfigError{Keys: missing}
+	}
+
+	return nil
+}
+
+// requiresConfig checks whether some of the fields have neither a default value nor can be skipped
+func requiresConfig(fields []configField) bool {
+	for _, field := range fields {
+		if !field.tag.hasDefault && !field.skippable {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,259 @@
+package di_test
+
+import (
+	"flag"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HnH/di"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestConfigSuite(t *testing.T) {
+	suite.Run(t, new(ConfigSuite))
+}
+
+type ConfigSuite struct {
+	container di.Container
+	resolver  di.Resolver
+
+	suite.Suite
+}
+
+func (suite *ConfigSuite) SetupSuite() {
+	suite.container = di.NewContainer()
+	suite.resolver = di.NewResolver(suite.container)
+}
+
+func (suite *ConfigSuite) TearDownTest() {
+	suite.container.Reset()
+}
+
+func (suite *ConfigSuite) requireErrorContains(err error, contains string) {
+	suite.Require().Error(err)
+	suite.Require().Contains(err.Error(), contains)
+}
+
+func (suite *ConfigSuite) bindConfig(sources ...di.ConfigSource) {
+	suite.Require().NoError(suite.container.Singleton(func() di.ConfigSource {
+		return di.Config(sources...)
+	}))
+}
+
+func (suite *ConfigSuite) TestFill() {
+	suite.bindConfig(di.MapSource(map[string]string{
+		"db.dsn":       "mysql://localhost",
+		"http.port":    "8080",
+		"http.timeout": "1m30s",
+		"debug":        "true",
+		"ratio":        "0.5",
+		"hosts":        "a, b,c",
+		"ports":        "1,2",
+		"ip":           "127.0.0.1",
+		"workers":      "16",
+	}))
+
+	var target = struct {
+		DSN     string        `di:"config=db.dsn"`
+		Port    int           `di:"config=http.port"`
+		Timeout time.Duration `di:"config=http.timeout"`
+		Debug   bool          `di:"config=debug"`
+		Ratio   float64       `di:"config=ratio"`
+		Hosts   []string      `di:"config=hosts"`
+		Ports   []uint16      `di:"config=ports"`
+		IP      net.IP        `di:"config=ip"`
+		workers int           `di:"config=workers"`
+	}{}
+
+	suite.Require().NoError(suite.resolver.Fill(&target))
+	suite.Require().Equal("mysql://localhost", target.DSN)
+	suite.Require().Equal(8080, target.Port)
+	suite.Require().Equal(90*time.Second, target.Timeout)
+	suite.Require().True(target.Debug)
+	suite.Require().Equal(0.5, target.Ratio)
+	suite.Require().Equal([]string{"a", "b", "c"}, target.Hosts)
+	suite.Require().Equal([]uint16{1, 2}, target.Ports)
+	suite.Require().Equal("127.0.0.1", target.IP.String())
+	suite.Require().Equal(16, target.workers)
+}
+
+func (suite *ConfigSuite) TestFillDefaults() {
+	suite.bindConfig(di.MapSource(map[string]string{"http.port": "8080"}))
+
+	var target = struct {
+		Port  int      `di:"config=http.port,default=80"`
+		Host  string   `di:"config=http.host,default=localhost"`
+		Hosts []string `di:"config=hosts,default=a,b"`
+		Empty string   `di:"config=empty,default="`
+	}{}
+
+	suite.Require().NoError(suite.resolver.Fill(&target))
+	suite.Require().Equal(8080, target.Port)
+	suite.Require().Equal("localhost", target.Host)
+	suite.Require().Equal([]string{"a", "b"}, target.Hosts)
+	suite.Require().Equal("", target.Empty)
+}
+
+func (suite *ConfigSuite) TestFillMissing() {
+	suite.bindConfig(di.MapSource(map[string]string{}))
+
+	var target = struct {
+		DSN     string `di:"config=db.dsn"`
+		Port    int    `di:"config=http.port"`
+		Timeout int    `di:"config=http.timeout,omitempty"`
+	}{}
+
+	suite.requireErrorContains(suite.resolver.Fill(&target), "di: missing required config key(s): db.dsn, http.port: filling")
+	suite.Require().EqualError(&di.ConfigError{Keys: []string{"a", "b"}}, "di: missing required config key(s): a, b")
+}
+
+func (suite *ConfigSuite) TestFillInvalidValue() {
+	suite.bindConfig(di.MapSource(map[string]string{"http.port": "eighty"}))
+
+	var target = struct {
+		Port int `di:"config=http.port"`
+	}{}
+
+	suite.requireErrorContains(suite.resolver.Fill(&target), "di: invalid value for config key http.port")
+}
+
+func (suite *ConfigSuite) TestFillUnsupportedType() {
+	suite.bindConfig(di.MapSource(map[string]string{"shape": "circle"}))
+
+	var target = struct {
+		Shape Shape `di:"config=shape"`
+	}{}
+
+	suite.requireErrorContains(suite.resolver.Fill(&target), "unsupported type di_test.Shape")
+}
+
+func (suite *ConfigSuite) TestFillInvalidTag() {
+	suite.bindConfig()
+
+	var target = struct {
+		DSN string `di:"config="`
+	}{}
+
+	suite.requireErrorContains(suite.resolver.Fill(&target), "di: DSN has an invalid struct tag")
+
+	var misplaced = struct {
+		Port int `di:"config=http.port,omitempty,default=8080"`
+	}{}
+
+	suite.requireErrorContains(suite.resolver.Fill(&misplaced), "di: Port has an invalid struct tag: omitempty must be the last option")
+}
+
+func (suite *ConfigSuite) TestFillUnbound() {
+	var target = struct {
+		DSN string `di:"config=db.dsn"`
+	}{}
+
+	suite.requireErrorContains(suite.resolver.Fill(&target), "di: no binding found for di.ConfigSource")
+
+	// defaults and skippable fields don't require a source
+	var optional = struct {
+		Port  int  `di:"config=http.port,default=8080"`
+		Debug bool `di:"config=debug,omitempty"`
+	}{}
+
+	suite.Require().NoError(suite.resolver.Fill(&optional))
+	suite.Require().Equal(8080, optional.Port)
+	suite.Require().False(optional.Debug)
+}
+
+func (suite *ConfigSuite) TestPrecedence() {
+	var src = di.Config(
+		di.MapSource(map[string]string{"a": "first"}),
+		di.MapSource(map[string]string{"a": "second", "b": "second"}),
+	)
+
+	var val, ok = src.Lookup("a")
+	suite.Require().True(ok)
+	suite.Require().Equal("first", val)
+
+	val, ok = src.Lookup("b")
+	suite.Require().True(ok)
+	suite.Require().Equal("second", val)
+
+	_, ok = src.Lookup("c")
+	suite.Require().False(ok)
+}
+
+func (suite *ConfigSuite) TestEnvSource() {
+	suite.T().Setenv("APP_DB_DSN", "env-dsn")
+	suite.T().Setenv("HTTP_READ_TIMEOUT", "5s")
+
+	var val, ok = di.EnvSource("app").Lookup("db.dsn")
+	suite.Require().True(ok)
+	suite.Require().Equal("env-dsn", val)
+
+	val, ok = di.EnvSource("").Lookup("http.read-timeout")
+	suite.Require().True(ok)
+	suite.Require().Equal("5s", val)
+
+	_, ok = di.EnvSource("app").Lookup("http.port")
+	suite.Require().False(ok)
+}
+
+func (suite *ConfigSuite) TestFlagSource() {
+	var fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Int("http.port", 80, "")
+	fs.String("db.dsn", "default-dsn", "")
+	suite.Require().NoError(fs.Parse([]string{"-http.port", "8080"}))
+
+	var val, ok = di.FlagSource(fs).Lookup("http.port")
+	suite.Require().True(ok)
+	suite.Require().Equal("8080", val)
+
+	_, ok = di.FlagSource(fs).Lookup("db.dsn")
+	suite.Require().False(ok)
+}
+
+func (suite *ConfigSuite) TestJSONSource() {
+	var src, err = di.JSONSource(strings.NewReader(`{
+		"db": {"dsn": "json-dsn", "pool": {"size": 10}},
+		"hosts": ["a", "b"],
+		"servers": [{"name": "x"}],
+		"debug": false,
+		"nothing": null
+	}`))
+
+	suite.Require().NoError(err)
+
+	for key, expected := range map[string]string{
+		"db.dsn":         "json-dsn",
+		"db.pool.size":   "10",
+		"hosts":          "a,b",
+		"servers.0.name": "x",
+		"debug":          "false",
+	} {
+		var val, ok = src.Lookup(key)
+		suite.Require().True(ok, key)
+		suite.Require().Equal(expected, val, key)
+	}
+
+	_, ok := src.Lookup("nothing")
+	suite.Require().False(ok)
+
+	_, err = di.JSONSource(strings.NewReader(`[`))
+	suite.requireErrorContains(err, "di: invalid json config")
+}
+
+func (suite *ConfigSuite) TestJSONFileSource() {
+	var path = filepath.Join(suite.T().TempDir(), "config.json")
+	suite.Require().NoError(os.WriteFile(path, []byte(`{"http": {"port": 8080}}`), 0o600))
+
+	var src, err = di.JSONFileSource(path)
+	suite.Require().NoError(err)
+
+	var val, ok = src.Lookup("http.port")
+	suite.Require().True(ok)
+	suite.Require().Equal("8080", val)
+
+	_, err = di.JSONFileSource(filepath.Join(suite.T().TempDir(), "missing.json"))
+	suite.requireErrorContains(err, "di: unable to open json config")
+}
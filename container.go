@@ -6,7 +6,11 @@ import (
 	"fmt"
 	"reflect"
 	"runtime"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Container is responsible for abstraction binding
@@ -14,7 +18,16 @@ type Container interface {
 	Singleton(constructor any, opts ...Option) error
 	Factory(constructor any, opts ...Option) error
 	Implementation(implementation any, opts ...Option) error
+	Value(name string, value any, opts ...Option) error
 	ListBindings(reflect.Type) (map[string]Binding, error)
+	Bindings() []BindingInfo
+	ID() uint64
+	Clone(opts ...Option) (Container, error)
+	Snapshot() Snapshot
+	Restore(Snapshot)
+	Profiles() []string
+	Build() error
+	Validate() error
 	Reset()
 }
 
@@ -23,21 +36,49 @@ type Provider interface {
 	Provide(Container) error
 }
 
-// Constructor implements a `Construct()` method which is called either after binding to container in case of singleton or after factory method was called.
+// Constructor implements a `Construct()` method which is called either after binding to container in case of singleton or implementation,
+// or after factory method was called. See Initializer for other post-construct hooks.
 type Constructor interface {
 	Construct(context.Context) error
 }
 
+// containerSeq is used to generate container IDs
+var containerSeq uint64
+
 // NewContainer creates a new instance of the Container
-func NewContainer() Container {
+func NewContainer(opts ...Option) Container {
+	var options = newContainerOptions(opts)
+
 	return &container{
-		bindings: make(map[reflect.Type]map[string]Binding),
+		id:        atomic.AddUint64(&containerSeq, 1),
+		bindings:  make(map[reflect.Type]map[string]Binding),
+		inactive:  make(map[reflect.Type]map[string]Binding),
+		profiles:  options.profiles,
+		observers: options.observers,
+		profiler:  options.profiler,
+		recover:   options.recover,
 	}
 }
 
 type container struct {
+	id        uint64
+	bindings  map[reflect.Type]map[string]Binding
+	inactive  map[reflect.Type]map[string]Binding // bindings skipped because they don't match active profiles or conditions
+	pending   []pendingBinding                    // conditional bindings waiting for Build()
+	profiles  []string
+	observers observers
+	profiler  *Profiler
+	recover   bool   // convert panics of constructors into errors
+	seq       uint64 // number of declarations made so far
+	lock      sync.RWMutex
+}
+
+// Snapshot holds a copy of container bindings that can be restored later
+type Snapshot struct {
 	bindings map[reflect.Type]map[string]Binding
-	lock     sync.RWMutex
+	inactive map[reflect.Type]map[string]Binding
+	pending  []pendingBinding
+	seq      uint64
 }
 
 // DefaultBindName is the name that is used in containers by default when binding values.
@@ -45,10 +86,72 @@ const DefaultBindName = "default"
 
 // Binding holds either singleton instance or factory method for a binding
 type Binding struct {
-	factory  any    // factory method that creates the appropriate implementation of the abstraction
-	instance any    // instance stored for reusing in singleton bindings
-	caller   string // caller stores information where the binding was declared from
-	fill     bool   // call Fill() on a returned instance after it's resolution
+	factory      any           // factory method that creates the appropriate implementation of the abstraction
+	instance     any           // instance stored for reusing in singleton bindings
+	constructor  any           // constructor singleton instance was created with
+	output       int           // index of the constructor returned value singleton instance was taken from
+	seq          uint64        // sequence number of the declaration binding was created by
+	caller       string        // caller stores information where the binding was declared from
+	fill         bool          // call Fill() on a returned instance after it's resolution
+	optionalArgs bool          // pass zero values to factory method arguments that can't be resolved
+	namedArgs    []NamedKey    // keys constructor arguments are resolved with by name
+	hooks        bool          // call Construct() and Init() methods with any resolvable arguments
+	setters      *setterPolicy // setter methods called on a returned instance, nil if setter injection is disabled
+	injected     []string      // setter methods called on a singleton instance
+	retry        retryPolicy   // how failed constructor and post-construct hook calls are retried
+	timeout      time.Duration // time construction of an instance is limited with
+	profiles     []string      // profiles binding is active for
+	conditions   []Condition   // conditions binding was registered on
+	resolutions  *uint64       // number of times binding was resolved, shared between copies of a binding
+	container    uint64        // ID of a container binding is stored in
+	abstraction  reflect.Type  // abstraction binding is stored under
+	name         string        // name binding is stored under
+}
+
+// pendingBinding is a conditional binding waiting for Build() to be evaluated
+type pendingBinding struct {
+	constructor any // constructor or an instance in case of Implementation()
+	opts        bindOptions
+}
+
+// outputs lists abstractions and names the declaration binds following the naming rules of bind().
+// Returned bindings only carry an index of the constructor returned value.
+func (self pendingBinding) outputs() []bindingEntry {
+	var names = self.opts.names
+	if len(names) == 0 {
+		names = []string{DefaultBindName}
+	}
+
+	if self.opts.implementation {
+		return []bindingEntry{{abstraction: self.opts.bindingType(self.constructor), name: names[0]}}
+	}
+
+	var ref = reflect.TypeOf(self.constructor)
+	var numRealInstances = ref.NumOut()
+	if numRealInstances > 0 && isError(ref.Out(numRealInstances-1)) {
+		numRealInstances--
+	}
+
+	var out = make([]bindingEntry, 0, numRealInstances*len(names))
+	for i := 0; i < numRealInstances; i++ {
+		switch {
+		case self.opts.factory:
+			out = append(out, bindingEntry{abstraction: ref.Out(i), name: names[0], binding: Binding{output: i}})
+
+		case numRealInstances > 1 && len(names) > 1:
+			out = append(out, bindingEntry{abstraction: ref.Out(i), name: names[i%len(names)], binding: Binding{output: i}})
+
+		case numRealInstances > 1:
+			out = append(out, bindingEntry{abstraction: ref.Out(i), name: names[0], binding: Binding{output: i}})
+
+		default:
+			for _, name := range names {
+				out = append(out, bindingEntry{abstraction: ref.Out(i), name: name, binding: Binding{output: i}})
+			}
+		}
+	}
+
+	return out
 }
 
 func (self *container) getResolver() *resolver {
@@ -79,57 +182,126 @@ func (self *container) bind(constructor any, opts bindOptions) (err error) {
 		numRealInstances--
 	}
 
-	var instances []reflect.Value
+	if opts.names == nil {
+		opts.names = []string{DefaultBindName}
+	}
+
+	if opts.setters != nil && opts.setters.err != nil {
+		return opts.setters.err
+	}
+
+	// shape of a declaration is checked regardless of whether it's active
 	switch {
-	case !opts.factory:
-		if numRealInstances > 1 && len(opts.names) > 1 && numRealInstances != len(opts.names) {
-			return errors.New("di: the constructor that returns multiple values must be called with either one name or number of names equal to number of values")
-		}
+	case !opts.factory && numRealInstances > 1 && len(opts.names) > 1 && numRealInstances != len(opts.names):
+		return errors.New("di: the constructor that returns multiple values must be called with either one name or number of names equal to number of values")
+
+	case opts.factory && (ref.NumOut() == 2 && !isError(ref.Out(1)) || ref.NumOut() > 2):
+		return errors.New("di: factory resolvers must return exactly one value and optionally one error")
+	}
+
+	var (
+		bound    []Event
+		elapsed  time.Duration
+		injected = make([][]string, numRealInstances)
+	)
+
+	defer func() {
+		if err != nil {
+			self.observers.notify(func(o Observer) {
+				o.OnError(Event{Container: self.id, Abstraction: ref, Kind: opts.kind(), Caller: opts.caller, Err: err})
+			})
 
-		if instances, err = self.getResolver().invoke(constructor); err != nil {
 			return
 		}
 
-		for i := 0; i < numRealInstances; i++ {
-			if opts.fill {
-				if err = self.getResolver().Fill(instances[i].Interface()); err != nil {
-					return
-				}
+		// constructor is called once per declaration, while its instances may be bound under several names
+		if !opts.factory && len(bound) > 0 {
+			var e = bound[0]
+			e.Duration = elapsed
+			self.observers.notify(func(o Observer) { o.OnConstruct(e) })
+		}
+
+		for _, e := range bound {
+			self.observers.notify(func(o Observer) { o.OnBind(e) })
+		}
+	}()
+
+	if !self.isActive(opts.profiles) {
+		self.lock.Lock()
+		defer self.lock.Unlock()
+
+		var bnd = declaration(constructor, opts, self.nextSeq())
+		for _, e := range (pendingBinding{constructor: constructor, opts: opts}).outputs() {
+			bnd.output = e.binding.output
+			self.addInactive(e.abstraction, e.name, bnd)
+		}
+
+		return nil
+	}
+
+	// conditional bindings are postponed until Build()
+	if len(opts.conditions) > 0 && !opts.building {
+		self.lock.Lock()
+		defer self.lock.Unlock()
+
+		self.pending = append(self.pending, pendingBinding{constructor: constructor, opts: opts})
+
+		return nil
+	}
+
+	var instances []reflect.Value
+	if !opts.factory {
+		var (
+			start = time.Now()
+			exit  = self.profilers().enter(funcName(constructor))
+		)
+
+		err = self.getResolver().guard(ref.Out(0), opts.names[0], opts.caller, opts.timeout, func(rsl *resolver) (err error) {
+			if err = opts.retry.do(rsl.context(), func() (err error) {
+				instances, err = rsl.invoke(constructor, opts.optionalArgs, opts.namedArgs)
+				return
+			}); err != nil {
+				return
 			}
 
-			if t, ok := instances[i].Interface().(Constructor); ok {
-				if _, err = self.getResolver().invoke(t.Construct); err != nil {
+			var decl = declaration(constructor, opts, 0)
+			for i := 0; i < numRealInstances; i++ {
+				if injected[i], err = rsl.construct(instances[i].Interface(), decl); err != nil {
 					return
 				}
 			}
+
+			return
+		})
+
+		if exit(); err != nil {
+			return
 		}
 
-	case opts.factory && (ref.NumOut() == 2 && !isError(ref.Out(1)) || ref.NumOut() > 2):
-		return errors.New("di: factory resolvers must return exactly one value and optionally one error")
+		elapsed = time.Since(start)
 	}
 
 	self.lock.Lock()
 	defer self.lock.Unlock()
 
-	for i := 0; i < numRealInstances; i++ {
-		if _, ok := self.bindings[ref.Out(i)]; !ok {
-			self.bindings[ref.Out(i)] = make(map[string]Binding)
-		}
-
-		var _, file, line, _ = runtime.Caller(2)
+	var seq = self.nextSeq()
 
-		if opts.names == nil {
-			opts.names = []string{DefaultBindName}
-		}
+	for i := 0; i < numRealInstances; i++ {
+		var (
+			name = opts.names[0]
+			bnd  = declaration(constructor, opts, seq)
+		)
 
-		var name = opts.names[0]
+		bnd.output = i
 
 		// Factory method
 		if opts.factory {
-			self.bindings[ref.Out(i)][name] = Binding{factory: constructor, caller: fmt.Sprintf("%s:%d", file, line), fill: opts.fill}
+			bound = append(bound, self.store(ref.Out(i), name, bnd))
 			continue
 		}
 
+		bnd.instance, bnd.injected = instances[i].Interface(), injected[i]
+
 		// Singleton instances
 		// if there is more than one instance returned from constructor - use appropriate name for it
 		if numRealInstances > 1 {
@@ -137,53 +309,152 @@ func (self *container) bind(constructor any, opts bindOptions) (err error) {
 				name = opts.names[i]
 			}
 
-			self.bindings[ref.Out(i)][name] = Binding{instance: instances[i].Interface(), caller: fmt.Sprintf("%s:%d", file, line), fill: opts.fill}
+			bound = append(bound, self.store(ref.Out(i), name, bnd))
 			continue
 		}
 
 		// if only one instance is returned from constructor - bind it under all provided names
 		for _, name = range opts.names {
-			self.bindings[ref.Out(i)][name] = Binding{instance: instances[i].Interface(), caller: fmt.Sprintf("%s:%d", file, line), fill: opts.fill}
+			bound = append(bound, self.store(ref.Out(i), name, bnd))
 		}
 	}
 
 	return nil
 }
 
+// store attaches a binding to the container and returns an event describing it, container lock must be held
+func (self *container) store(abstraction reflect.Type, name string, bnd Binding) Event {
+	if _, ok := self.bindings[abstraction]; !ok {
+		self.bindings[abstraction] = make(map[string]Binding)
+	}
+
+	bnd = bnd.attach(self.id, abstraction, name)
+	self.bindings[abstraction][name] = bnd
+
+	return bnd.event()
+}
+
+// profilers returns a profiler of the container if there is one
+func (self *container) profilers() profilers {
+	if self.profiler == nil {
+		return nil
+	}
+
+	return profilers{self.profiler}
+}
+
 // Singleton binds value(s) returned from constructor as a singleton objects of related types.
 func (self *container) Singleton(constructor any, opts ...Option) error {
-	return self.bind(constructor, newBindOptions(opts))
+	var options = newBindOptions(opts)
+	options.caller = callerLocation(2)
+
+	return self.bind(constructor, options)
 }
 
 // Factory binds constructor as a factory method of related type.
 func (self *container) Factory(constructor any, opts ...Option) error {
 	var options = newBindOptions(opts)
 	options.factory = true
+	options.caller = callerLocation(2)
 
 	return self.bind(constructor, options)
 }
 
 // Implementation receives ready instance and binds it to its REAL type, which means that declared abstract variable type (interface) is ignored
 func (self *container) Implementation(implementation any, opts ...Option) error {
-	self.lock.Lock()
-	defer self.lock.Unlock()
+	var options = newBindOptions(opts)
+	options.implementation = true
+	options.caller = callerLocation(2)
+
+	return self.implementation(implementation, options)
+}
 
-	var ref = reflect.TypeOf(implementation)
-	if _, ok := self.bindings[ref]; !ok {
-		self.bindings[ref] = make(map[string]Binding)
+// Value binds a value, e.g. a scalar, a string or a struct, to its type under provided name. Values are treated like
+// implementations, see Key for binding values under interface types.
+func (self *container) Value(name string, value any, opts ...Option) error {
+	if value == nil {
+		return errors.New("di: the value must not be nil")
 	}
 
 	var options = newBindOptions(opts)
+	options.implementation = true
+	options.names = []string{name}
+	if options.caller == "" {
+		options.caller = callerLocation(2)
+	}
+
+	return self.implementation(value, options)
+}
+
+func (self *container) implementation(implementation any, options bindOptions) (err error) {
+	var ref = options.bindingType(implementation)
 	if len(options.names) == 0 {
 		options.names = []string{DefaultBindName}
 	}
 
-	var _, file, line, _ = runtime.Caller(1)
-	self.bindings[ref][options.names[0]] = Binding{instance: implementation, caller: fmt.Sprintf("%s:%d", file, line)}
+	var bound *Event
+	defer func() {
+		if err != nil {
+			self.observers.notify(func(o Observer) {
+				o.OnError(Event{Container: self.id, Abstraction: ref, Name: options.names[0], Kind: KindImplementation, Caller: options.caller, Err: err})
+			})
+
+			return
+		}
+
+		if bound != nil {
+			self.observers.notify(func(o Observer) { o.OnBind(*bound) })
+		}
+	}()
+
+	if options.setters != nil && options.setters.err != nil {
+		return options.setters.err
+	}
+
+	if !self.isActive(options.profiles) {
+		self.lock.Lock()
+		defer self.lock.Unlock()
+
+		self.addInactive(ref, options.names[0], declaration(implementation, options, self.nextSeq()))
+
+		return nil
+	}
+
+	// conditional bindings are postponed until Build()
+	if len(options.conditions) > 0 && !options.building {
+		self.lock.Lock()
+		defer self.lock.Unlock()
+
+		self.pending = append(self.pending, pendingBinding{constructor: implementation, opts: options})
+
+		return nil
+	}
+
+	// implementations are filled and their hooks are called just like singleton instances
+	var (
+		bnd     = declaration(implementation, options, 0)
+		setters []string
+	)
+
+	if err = self.getResolver().guard(ref, options.names[0], options.caller, options.timeout, func(rsl *resolver) (err error) {
+		setters, err = rsl.construct(implementation, bnd)
+		return
+	}); err != nil {
+		return
+	}
+
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	bnd.seq, bnd.instance, bnd.injected = self.nextSeq(), implementation, setters
+
+	var event = self.store(ref, options.names[0], bnd)
+	bound = &event
 
 	return nil
 }
 
+// ListBindings returns all active bindings of an abstraction
 func (self *container) ListBindings(abstraction reflect.Type) (map[string]Binding, error) {
 	self.lock.RLock()
 	defer self.lock.RUnlock()
@@ -196,6 +467,178 @@ func (self *container) ListBindings(abstraction reflect.Type) (map[string]Bindin
 	return bnds, nil
 }
 
+// Bindings describes all bindings of the container in declaration order: active bindings go first, then inactive
+// and pending ones. Bindings of the same abstraction are grouped together.
+func (self *container) Bindings() []BindingInfo {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	var out = make([]BindingInfo, 0, len(self.bindings))
+	for _, e := range sortedBindings(self.bindings) {
+		out = append(out, e.binding.info(self.id, e.abstraction, e.name, StateActive))
+	}
+
+	for _, e := range sortedBindings(self.inactive) {
+		out = append(out, e.binding.info(self.id, e.abstraction, e.name, StateInactive))
+	}
+
+	for _, p := range self.pending {
+		var bnd = declaration(p.constructor, p.opts, 0)
+		for _, e := range p.outputs() {
+			out = append(out, bnd.info(self.id, e.abstraction, e.name, StatePending))
+		}
+	}
+
+	return out
+}
+
+// ID returns a process-wide unique container identifier
+func (self *container) ID() uint64 {
+	return self.id
+}
+
+// Clone creates a new container with a copy of bindings. Singleton instances are shared between containers unless
+// WithReinstantiate() option is provided, in that case singleton constructors are called again against the clone in
+// the order they were originally bound. Instances bound with Implementation() are always shared.
+func (self *container) Clone(opts ...Option) (Container, error) {
+	var snapshot = self.Snapshot()
+	var clone = &container{
+		id:        atomic.AddUint64(&containerSeq, 1),
+		bindings:  snapshot.bindings,
+		inactive:  snapshot.inactive,
+		pending:   snapshot.pending,
+		profiles:  self.Profiles(),
+		observers: self.observers,
+		profiler:  self.profiler,
+		recover:   self.recover,
+		seq:       snapshot.seq,
+	}
+
+	// clone counts its own resolutions
+	for t, list := range clone.bindings {
+		for name, bnd := range list {
+			list[name] = bnd.attach(clone.id, t, name)
+		}
+	}
+
+	if newCloneOptions(opts).reinstantiate {
+		if err := clone.reinstantiate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return clone, nil
+}
+
+// reinstantiate calls singleton constructors again and replaces shared instances with the new ones
+func (self *container) reinstantiate() error {
+	var groups = make(map[uint64][]Binding)
+
+	self.lock.RLock()
+	for _, list := range self.bindings {
+		for _, bnd := range list {
+			if bnd.constructor != nil {
+				groups[bnd.seq] = append(groups[bnd.seq], bnd)
+			}
+		}
+	}
+	self.lock.RUnlock()
+
+	var seqs = make([]uint64, 0, len(groups))
+	for seq := range groups {
+		seqs = append(seqs, seq)
+	}
+
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+
+	for _, seq := range seqs {
+		var bnd = groups[seq][0]
+
+		var (
+			exit      = self.profilers().enter(funcName(bnd.constructor))
+			instances []reflect.Value
+			injected  = make(map[int][]string)
+		)
+
+		var err = self.getResolver().guard(bnd.abstraction, bnd.name, bnd.caller, bnd.timeout, func(rsl *resolver) (err error) {
+			if err = bnd.retry.do(rsl.context(), func() (err error) {
+				instances, err = rsl.invoke(bnd.constructor, bnd.optionalArgs, bnd.namedArgs)
+				return
+			}); err != nil {
+				return
+			}
+
+			var replaced = make(map[int]bool)
+			for _, b := range groups[seq] {
+				if replaced[b.output] {
+					continue
+				}
+
+				if injected[b.output], err = rsl.construct(instances[b.output].Interface(), b); err != nil {
+					return
+				}
+
+				replaced[b.output] = true
+			}
+
+			return
+		})
+
+		if exit(); err != nil {
+			return err
+		}
+
+		self.lock.Lock()
+		for t, list := range self.bindings {
+			for name, b := range list {
+				if b.constructor != nil && b.seq == seq {
+					b.instance, b.injected = instances[b.output].Interface(), injected[b.output]
+					self.bindings[t][name] = b
+				}
+			}
+		}
+		self.lock.Unlock()
+	}
+
+	return nil
+}
+
+// Snapshot returns a copy of container bindings
+func (self *container) Snapshot() Snapshot {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	return Snapshot{
+		bindings: copyBindings(self.bindings),
+		inactive: copyBindings(self.inactive),
+		pending:  append([]pendingBinding(nil), self.pending...),
+		seq:      self.seq,
+	}
+}
+
+// Restore atomically replaces container bindings with the ones from a snapshot. Snapshot can be restored multiple times.
+func (self *container) Restore(snapshot Snapshot) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	self.bindings = copyBindings(snapshot.bindings)
+	self.inactive = copyBindings(snapshot.inactive)
+	self.pending = append([]pendingBinding(nil), snapshot.pending...)
+	self.seq = snapshot.seq
+}
+
+func copyBindings(src map[reflect.Type]map[string]Binding) map[reflect.Type]map[string]Binding {
+	var out = make(map[reflect.Type]map[string]Binding, len(src))
+	for t, list := range src {
+		out[t] = make(map[string]Binding, len(list))
+		for name, bnd := range list {
+			out[t][name] = bnd
+		}
+	}
+
+	return out
+}
+
 // Reset deletes all the existing bindings and empties the container instance.
 func (self *container) Reset() {
 	self.lock.Lock()
@@ -204,4 +647,216 @@ func (self *container) Reset() {
 	for k := range self.bindings {
 		delete(self.bindings, k)
 	}
+
+	for k := range self.inactive {
+		delete(self.inactive, k)
+	}
+
+	self.pending = nil
+}
+
+// Profiles returns a list of active profiles
+func (self *container) Profiles() []string {
+	var out = make([]string, len(self.profiles))
+	copy(out, self.profiles)
+
+	return out
+}
+
+// Build evaluates conditional bindings in the order they were declared. It stops at the first failed binding,
+// which is kept pending along with the following ones.
+// Conditions see all unconditional bindings regardless of their registration order and conditional bindings that were applied before.
+func (self *container) Build() error {
+	self.lock.Lock()
+	var pending = self.pending
+	self.pending = nil
+	self.lock.Unlock()
+
+	for i, p := range pending {
+		p.opts.building = true
+
+		if !self.conditionsMet(p.opts.conditions) {
+			self.skip(p)
+			continue
+		}
+
+		var err error
+		if p.opts.implementation {
+			err = self.implementation(p.constructor, p.opts)
+		} else {
+			err = self.bind(p.constructor, p.opts)
+		}
+
+		if err != nil {
+			// the failed binding stays pending, so that Build() and Validate() keep reporting it
+			self.lock.Lock()
+			self.pending = append(pending[i:], self.pending...)
+			self.lock.Unlock()
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// conditionsMet checks whether all conditions are satisfied by the container
+func (self *container) conditionsMet(conditions []Condition) bool {
+	for _, c := range conditions {
+		if !c.Met(self) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// skip stores a pending binding with unmet conditions as inactive
+func (self *container) skip(p pendingBinding) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	var bnd = declaration(p.constructor, p.opts, self.nextSeq())
+	for _, e := range p.outputs() {
+		bnd.output = e.binding.output
+		self.addInactive(e.abstraction, e.name, bnd)
+	}
+}
+
+// Validate builds pending conditional bindings and checks that arguments of all bound factory methods can be resolved against the container.
+// Singleton constructors are not checked as long as they are called on binding.
+func (self *container) Validate() error {
+	if err := self.Build(); err != nil {
+		return err
+	}
+
+	var (
+		rsl      = self.getResolver()
+		problems []string
+	)
+
+	self.lock.RLock()
+	var factories = make(map[string]Binding)
+	for t, list := range self.bindings {
+		for name, bnd := range list {
+			if bnd.factory != nil && !bnd.optionalArgs {
+				factories[fmt.Sprintf("%s [%s]", t.String(), name)] = bnd
+			}
+		}
+	}
+	self.lock.RUnlock()
+
+	for key, bnd := range factories {
+		var ref = reflect.TypeOf(bnd.factory)
+		var names, err = argumentNames(ref, bnd.namedArgs)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s declared at [%s]: %s", key, bnd.caller, err.Error()))
+			continue
+		}
+
+		for i := 0; i < ref.NumIn(); i++ {
+			if isOptional(ref.In(i)) || ref.IsVariadic() && i == ref.NumIn()-1 {
+				continue
+			}
+
+			if names[i] == DefaultBindName && isCollection(ref.In(i)) && rsl.hasBindings(ref.In(i).Elem()) {
+				continue
+			}
+
+			if _, err = rsl.getBinding(ref.In(i), names[i]); err != nil {
+				if names[i] != DefaultBindName {
+					err = fmt.Errorf("%w [%s]", err, names[i])
+				}
+
+				problems = append(problems, fmt.Sprintf("%s declared at [%s]: %s", key, bnd.caller, err.Error()))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("di: container validation failed: %s", strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// isActive checks whether a binding with provided profile condition is active in the container
+func (self *container) isActive(profiles []string) bool {
+	if len(profiles) == 0 {
+		return true
+	}
+
+	for _, p := range profiles {
+		var negate = strings.HasPrefix(p, "!")
+		if self.hasProfile(strings.TrimPrefix(p, "!")) != negate {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (self *container) hasProfile(profile string) bool {
+	for _, p := range self.profiles {
+		if p == profile {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nextSeq returns a sequence number for a new declaration, container lock must be held
+func (self *container) nextSeq() uint64 {
+	self.seq++
+	return self.seq
+}
+
+// declaration creates a binding without an instance that describes how it was declared
+func declaration(constructor any, opts bindOptions, seq uint64) Binding {
+	var bnd = Binding{seq: seq, caller: opts.caller, fill: opts.fill, optionalArgs: opts.optionalArgs, namedArgs: opts.namedArgs, hooks: opts.hooks, setters: opts.setters, retry: opts.retry, timeout: opts.timeout, profiles: opts.profiles, conditions: opts.conditions}
+
+	switch {
+	case opts.implementation:
+
+	case opts.factory:
+		bnd.factory = constructor
+
+	default:
+		bnd.constructor = constructor
+	}
+
+	return bnd
+}
+
+// addInactive stores a binding skipped because of profile mismatch, container lock must be held
+func (self *container) addInactive(abstraction reflect.Type, name string, bnd Binding) {
+	if _, ok := self.inactive[abstraction]; !ok {
+		self.inactive[abstraction] = make(map[string]Binding)
+	}
+
+	self.inactive[abstraction][name] = bnd
+}
+
+// callerLocation returns file:line of a function that is skip frames above the caller
+func callerLocation(skip int) string {
+	var _, file, line, _ = runtime.Caller(skip)
+	return fmt.Sprintf("%s:%d", file, line)
+}
+
+// ValidateProfiles provides bindings into a new container for each profile and validates it
+func ValidateProfiles(provider Provider, profiles ...string) error {
+	for _, profile := range profiles {
+		var c = NewContainer(WithProfiles(profile))
+		if err := provider.Provide(c); err != nil {
+			return fmt.Errorf("di: profile %s: %w", profile, err)
+		}
+
+		if err := c.Validate(); err != nil {
+			return fmt.Errorf("di: profile %s: %w", profile, err)
+		}
+	}
+
+	return nil
 }
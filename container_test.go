@@ -3,6 +3,8 @@ package di_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/HnH/di"
@@ -157,6 +159,25 @@ func (suite *ContainerSuite) TestSingletonNonResolvableArgs() {
 	}), "di: no binding found for di_test.Shape")
 }
 
+func (suite *ContainerSuite) TestSingletonOptionalArgs() {
+	suite.Require().NoError(suite.container.Singleton(func(s Shape, o di.Optional[Shape]) Database {
+		suite.Require().Nil(s)
+		suite.Require().False(o.Present)
+		return &MySQL{}
+	}, di.WithOptionalArgs()))
+}
+
+func (suite *ContainerSuite) TestFactoryOptionalArgs() {
+	suite.Require().NoError(suite.container.Factory(func(s Shape) Database {
+		suite.Require().Nil(s)
+		return &MySQL{}
+	}, di.WithOptionalArgs()))
+
+	var db Database
+	suite.Require().NoError(suite.resolver.Resolve(&db))
+	suite.Require().IsType(&MySQL{}, db)
+}
+
 func (suite *ContainerSuite) TestSingletonNamed() {
 	suite.Require().NoError(suite.container.Singleton(func() Shape {
 		return &Circle{a: 13}
@@ -263,6 +284,353 @@ func (suite *ContainerSuite) TestImplementationWithoutName() {
 	suite.Require().NoError(suite.resolver.Resolve(&c, di.WithName("theCircle")))
 }
 
+func (suite *ContainerSuite) TestClone() {
+	suite.Require().NoError(suite.container.Singleton(newCircle))
+	suite.Require().NoError(suite.container.Factory(newMySQL))
+
+	var clone, err = suite.container.Clone()
+	suite.Require().NoError(err)
+
+	var original, cloned Shape
+	suite.Require().NoError(suite.resolver.Resolve(&original))
+	suite.Require().NoError(di.NewResolver(clone).Resolve(&cloned))
+	suite.Require().Same(original, cloned)
+
+	suite.Require().NoError(clone.Singleton(newRectangle))
+	suite.Require().NoError(suite.resolver.Resolve(&original))
+	suite.Require().IsType(&Circle{}, original)
+
+	var db Database
+	suite.Require().NoError(di.NewResolver(clone).Resolve(&db))
+}
+
+func (suite *ContainerSuite) TestCloneReinstantiate() {
+	var calls int
+	suite.Require().NoError(suite.container.Singleton(context.Background))
+	suite.Require().NoError(suite.container.Singleton(func() (Shape, Database) {
+		calls++
+		return &Circle{a: calls}, newMongoDB(nil)
+	}, di.WithName("a", "b")))
+	suite.Require().NoError(suite.container.Implementation(&Circle{a: 42}))
+
+	var clone, err = suite.container.Clone(di.WithReinstantiate())
+	suite.Require().NoError(err)
+	suite.Require().Equal(2, calls)
+
+	var (
+		original = di.NewResolver(suite.container)
+		cloned   = di.NewResolver(clone)
+		s1, s2   Shape
+		db1, db2 Database
+		c1, c2   *Circle
+	)
+
+	suite.Require().NoError(original.Resolve(&s1, di.WithName("a")))
+	suite.Require().NoError(cloned.Resolve(&s2, di.WithName("a")))
+	suite.Require().NotSame(s1, s2)
+	suite.Require().Equal(2, s2.GetArea())
+
+	suite.Require().NoError(original.Resolve(&db1, di.WithName("b")))
+	suite.Require().NoError(cloned.Resolve(&db2, di.WithName("b")))
+	suite.Require().NotSame(db1, db2)
+	suite.Require().False(db2.(*MongoDB).constructCalled.IsZero())
+
+	suite.Require().NoError(original.Resolve(&c1))
+	suite.Require().NoError(cloned.Resolve(&c2))
+	suite.Require().Same(c1, c2)
+}
+
+func (suite *ContainerSuite) TestCloneReinstantiateError() {
+	var fail bool
+	suite.Require().NoError(suite.container.Singleton(func() (Shape, error) {
+		if fail {
+			return nil, errors.New("dummy error")
+		}
+
+		return &Circle{}, nil
+	}))
+
+	fail = true
+	var _, err = suite.container.Clone(di.WithReinstantiate())
+	suite.Require().EqualError(err, "dummy error")
+
+	suite.container.Reset()
+	fail = false
+	suite.Require().NoError(suite.container.Singleton(context.Background))
+	suite.Require().NoError(suite.container.Singleton(func() Database {
+		if fail {
+			return newMongoDB(errors.New("construct error"))
+		}
+
+		return newMongoDB(nil)
+	}))
+
+	fail = true
+	_, err = suite.container.Clone(di.WithReinstantiate())
+	suite.Require().EqualError(err, "construct error")
+}
+
+func (suite *ContainerSuite) TestSnapshotRestore() {
+	suite.Require().NoError(suite.container.Singleton(newCircle))
+	suite.Require().NoError(suite.container.Singleton(newMySQL, di.WhenProfile("prod")))
+	suite.Require().NoError(suite.container.Factory(newMySQL, di.IfMissing[Database]()))
+
+	var snapshot = suite.container.Snapshot()
+
+	suite.Require().NoError(suite.container.Singleton(newRectangle))
+	suite.Require().NoError(suite.container.Build())
+	suite.container.Restore(snapshot)
+
+	var s Shape
+	suite.Require().NoError(suite.resolver.Resolve(&s))
+	suite.Require().IsType(&Circle{}, s)
+
+	var db Database
+	suite.Require().EqualError(suite.resolver.Resolve(&db), "di: no binding found for di_test.Database")
+	suite.Require().NoError(suite.container.Build())
+	suite.Require().NoError(suite.resolver.Resolve(&db))
+
+	suite.container.Reset()
+	suite.container.Restore(snapshot)
+	suite.Require().NoError(suite.resolver.Resolve(&s))
+	suite.Require().IsType(&Circle{}, s)
+}
+
+func (suite *ContainerSuite) TestBindings() {
+	suite.Require().NoError(suite.container.Factory(newMySQL, di.WithFill()))
+	suite.Require().NoError(suite.container.Singleton(newCircle, di.WithName("b", "a")))
+	suite.Require().NoError(suite.container.Implementation(&MySQL{}))
+	suite.Require().NoError(suite.container.Singleton(newRectangle, di.WhenProfile("prod")))
+	suite.Require().NoError(suite.container.Singleton(newRectangle, di.WithName("c"), di.IfPresent[Database]()))
+
+	var db Database
+	suite.Require().NoError(suite.resolver.Resolve(&db))
+	suite.Require().NoError(suite.resolver.Call(func(Database, di.Optional[*Circle]) {}))
+
+	var infos = suite.container.Bindings()
+	suite.Require().Len(infos, 6)
+
+	var actual = make([]string, 0, len(infos))
+	for _, info := range infos {
+		suite.Require().Equal(suite.container.ID(), info.Container)
+		suite.Require().Contains(info.Caller, "container_test.go")
+		actual = append(actual, fmt.Sprintf("%s %s [%s] %s %t", info.State, info.Abstraction, info.Name, info.Kind, info.Instantiated))
+	}
+
+	suite.Require().Equal([]string{
+		"active di_test.Database [default] factory false",
+		"active di_test.Shape [a] singleton true",
+		"active di_test.Shape [b] singleton true",
+		"active *di_test.MySQL [default] implementation true",
+		"inactive di_test.Shape [default] singleton false",
+		"pending di_test.Shape [c] singleton false",
+	}, actual)
+
+	suite.Require().Equal(reflect.TypeOf(newMySQL), infos[0].Constructor)
+	suite.Require().True(infos[0].Fill)
+	suite.Require().Equal(uint64(2), infos[0].Resolutions)
+	suite.Require().Empty(infos[0].Dependencies)
+	suite.Require().Equal(uint64(0), infos[1].Resolutions)
+	suite.Require().Nil(infos[3].Constructor)
+	suite.Require().Equal([]string{"prod"}, infos[4].Profiles)
+	suite.Require().Equal([]string{"if present di_test.Database [default]"}, infos[5].Conditions)
+
+	var clone, err = suite.container.Clone()
+	suite.Require().NoError(err)
+	suite.Require().NotEqual(suite.container.ID(), clone.ID())
+	suite.Require().Equal(clone.ID(), clone.Bindings()[0].Container)
+	suite.Require().Equal(uint64(0), clone.Bindings()[0].Resolutions)
+}
+
+func (suite *ContainerSuite) TestProfiles() {
+	var (
+		container = di.NewContainer(di.WithProfiles("prod"))
+		resolver  = di.NewResolver(container)
+		called    bool
+	)
+
+	suite.Require().Equal([]string{"prod"}, container.Profiles())
+
+	suite.Require().NoError(container.Singleton(func() Shape {
+		called = true
+		return &Circle{}
+	}, di.WhenProfile("local", "test")))
+	suite.Require().False(called)
+
+	suite.Require().NoError(container.Singleton(newRectangle, di.WhenProfile("prod", "staging")))
+	suite.Require().NoError(container.Factory(newMySQL, di.WhenProfile("!test")))
+	suite.Require().NoError(container.Implementation(&Circle{}, di.WhenProfile("test")))
+
+	var s Shape
+	suite.Require().NoError(resolver.Resolve(&s))
+	suite.Require().IsType(&Rectangle{}, s)
+
+	var db Database
+	suite.Require().NoError(resolver.Resolve(&db))
+
+	var c *Circle
+	suite.Require().EqualError(resolver.Resolve(&c), "di: no binding found for *di_test.Circle")
+}
+
+func (suite *ContainerSuite) TestProfilesMultiNaming() {
+	var container = di.NewContainer()
+
+	suite.Require().NoError(container.Singleton(func() (Shape, Database) {
+		return &Circle{}, &MySQL{}
+	}, di.WithName("shape", "db"), di.WhenProfile("prod")))
+
+	var lines = di.Ctx(context.Background()).SetContainer(container).Visualize()
+	suite.Require().Contains(lines, "  -> container [0] has [2] inactive type binding(s) for profile(s) []")
+}
+
+func (suite *ContainerSuite) TestProfilesShape() {
+	var container = di.NewContainer()
+
+	suite.Require().EqualError(
+		container.Factory(func() (Shape, Database) { return &Circle{}, &MySQL{} }, di.WhenProfile("prod")),
+		"di: factory resolvers must return exactly one value and optionally one error",
+	)
+
+	suite.Require().EqualError(
+		container.Singleton(func() (Shape, Database) { return &Circle{}, &MySQL{} }, di.WithName("a", "b", "c"), di.WhenProfile("prod")),
+		"di: the constructor that returns multiple values must be called with either one name or number of names equal to number of values",
+	)
+
+	suite.Require().EqualError(
+		container.Singleton(func() (Shape, Database) { return &Circle{}, &MySQL{} }, di.WithName("a", "b", "c"), di.IfMissing[Shape]()),
+		"di: the constructor that returns multiple values must be called with either one name or number of names equal to number of values",
+	)
+
+	suite.Require().Empty(container.Bindings())
+}
+
+func (suite *ContainerSuite) TestProfilesFromEnv() {
+	suite.T().Setenv("DI_TEST_PROFILES", "prod, staging,")
+	suite.Require().Equal([]string{"prod", "staging"}, di.NewContainer(di.WithProfilesFromEnv("DI_TEST_PROFILES")).Profiles())
+	suite.Require().Empty(di.NewContainer(di.WithProfilesFromEnv("DI_TEST_PROFILES_UNSET")).Profiles())
+}
+
+func (suite *ContainerSuite) TestIfMissing() {
+	// library module provides a default which is overridden by the application registered later
+	suite.Require().NoError(suite.container.Singleton(newRectangle, di.IfMissing[Shape]()))
+	suite.Require().NoError(suite.container.Singleton(newCircle))
+	suite.Require().NoError(suite.container.Build())
+
+	var s Shape
+	suite.Require().NoError(suite.resolver.Resolve(&s))
+	suite.Require().IsType(&Circle{}, s)
+
+	var lines = di.Ctx(context.Background()).SetContainer(suite.container).Visualize()
+	suite.Require().Regexp(`• \[default\] inactive, requires if missing di_test.Shape \[default\] declared at \[.+/container_test.go:\d+\]`, lines[len(lines)-1])
+
+	suite.container.Reset()
+
+	suite.Require().NoError(suite.container.Singleton(newRectangle, di.IfMissing[Shape]()))
+	suite.Require().EqualError(suite.resolver.Resolve(&s), "di: no binding found for di_test.Shape")
+	suite.Require().NoError(suite.container.Build())
+	suite.Require().NoError(suite.resolver.Resolve(&s))
+	suite.Require().IsType(&Rectangle{}, s)
+}
+
+func (suite *ContainerSuite) TestIfPresent() {
+	suite.Require().NoError(suite.container.Factory(newMySQL, di.IfPresent[Shape]("sql")))
+	suite.Require().NoError(suite.container.Implementation(&Circle{}, di.IfPresent[Shape]("sql"), di.WithName("circle")))
+	suite.Require().NoError(suite.container.Implementation(&Rectangle{}, di.IfPresent[Shape]()))
+
+	suite.Require().Contains(
+		di.Ctx(context.Background()).SetContainer(suite.container).Visualize(),
+		"  -> container [0] has [3] pending conditional binding(s)",
+	)
+
+	suite.Require().NoError(suite.container.Singleton(newCircle, di.WithName("sql")))
+	suite.Require().NoError(suite.container.Validate())
+
+	var db Database
+	suite.Require().NoError(suite.resolver.Resolve(&db))
+	suite.Require().IsType(&MySQL{}, db)
+
+	var c *Circle
+	suite.Require().NoError(suite.resolver.Resolve(&c, di.WithName("circle")))
+
+	var r *Rectangle
+	suite.Require().EqualError(suite.resolver.Resolve(&r), "di: no binding found for *di_test.Rectangle")
+}
+
+func (suite *ContainerSuite) TestConditionsOrder() {
+	// conditional bindings see conditional bindings that were applied before them
+	suite.Require().NoError(suite.container.Singleton(newCircle, di.IfMissing[Shape]()))
+	suite.Require().NoError(suite.container.Singleton(newRectangle, di.IfMissing[Shape]()))
+	suite.Require().NoError(suite.container.Singleton(func() (Shape, Database) {
+		return &Rectangle{}, &MySQL{}
+	}, di.IfMissing[Shape](), di.WithName("r", "db")))
+	suite.Require().NoError(suite.container.Build())
+
+	var s Shape
+	suite.Require().NoError(suite.resolver.Resolve(&s))
+	suite.Require().IsType(&Circle{}, s)
+
+	var db Database
+	suite.Require().EqualError(suite.resolver.Resolve(&db, di.WithName("db")), "di: no binding found for di_test.Database")
+}
+
+func (suite *ContainerSuite) TestConditionsBuildError() {
+	suite.Require().NoError(suite.container.Singleton(func() (Shape, error) {
+		return nil, errors.New("dummy error")
+	}, di.IfMissing[Shape]()))
+	suite.Require().NoError(suite.container.Factory(newMySQL, di.IfMissing[Database]()))
+
+	suite.Require().EqualError(suite.container.Build(), "dummy error")
+	suite.Require().EqualError(suite.container.Validate(), "dummy error")
+
+	var s Shape
+	suite.Require().EqualError(suite.resolver.Resolve(&s), "di: no binding found for di_test.Shape")
+
+	// declarations following the failed one are not evaluated either
+	var db Database
+	suite.Require().EqualError(suite.resolver.Resolve(&db), "di: no binding found for di_test.Database")
+
+	var infos = suite.container.Bindings()
+	suite.Require().Len(infos, 2)
+	suite.Require().Equal(di.StatePending, infos[0].State)
+	suite.Require().Equal(di.StatePending, infos[1].State)
+}
+
+func (suite *ContainerSuite) TestValidate() {
+	suite.Require().NoError(suite.container.Factory(func(s Shape, o di.Optional[Circle]) Database { return &MySQL{} }))
+	suite.Require().NoError(suite.container.Factory(func(db Database) Shape { return &Circle{} }))
+	suite.Require().NoError(suite.container.Factory(func(c *Circle) *MySQL { return &MySQL{} }, di.WithOptionalArgs()))
+	suite.Require().NoError(suite.container.Validate())
+
+	suite.Require().NoError(suite.container.Factory(func(db Database, c *Circle, r Rectangle) *Rectangle { return &Rectangle{} }))
+
+	var err = suite.container.Validate()
+	suite.Require().Error(err)
+	suite.Require().Regexp(`^di: container validation failed: `+
+		`\*di_test.Rectangle \[default\] declared at \[.+/container_test.go:\d+\]: di: no binding found for \*di_test.Circle; `+
+		`\*di_test.Rectangle \[default\] declared at \[.+/container_test.go:\d+\]: di: no binding found for di_test.Rectangle$`, err.Error())
+}
+
+func (suite *ContainerSuite) TestValidateProfiles() {
+	var provider = providerFunc(func(c di.Container) error {
+		if err := c.Singleton(newCircle, di.WhenProfile("prod")); err != nil {
+			return err
+		}
+
+		return c.Factory(func(s Shape) Database { return &MySQL{} })
+	})
+
+	suite.Require().NoError(di.ValidateProfiles(provider, "prod"))
+
+	var err = di.ValidateProfiles(provider, "prod", "test")
+	suite.Require().Error(err)
+	suite.Require().Regexp(`^di: profile test: di: container validation failed: `+
+		`di_test.Database \[default\] declared at \[.+/container_test.go:\d+\]: di: no binding found for di_test.Shape$`, err.Error())
+
+	suite.Require().EqualError(di.ValidateProfiles(providerFunc(func(c di.Container) error {
+		return c.Singleton("STRING!")
+	}), "prod"), "di: profile prod: di: the constructor must be a function")
+}
+
 func (suite *ContainerSuite) TestCoverageBump() {
 	suite.Require().NoError(di.Singleton(context.Background(), newCircle))
 	suite.Require().NoError(di.Factory(context.Background(), newCircle))
@@ -3,6 +3,7 @@ package di
 import (
 	"context"
 	"fmt"
+	"strings"
 )
 
 // Context describe DI context propagator capabilities
@@ -45,7 +46,7 @@ func (self *ctx) Container() Container {
 		return c
 	}
 
-	return globalContext.Container()
+	return global().Container()
 }
 
 // SetResolver puts container to a context
@@ -63,34 +64,46 @@ func (self *ctx) Resolver() Resolver {
 	return NewResolver(self.Container())
 }
 
+// Visualize describes bindings of all containers available to the Resolver
 func (self *ctx) Visualize() []string {
-	var out = make([]string, 0, 100)
-
-	var r, ok = self.Resolver().(*resolver)
-	if !ok {
-		return out
-	}
+	var (
+		out        = make([]string, 0, 100)
+		containers = self.Resolver().Containers()
+	)
+
+	out = append(out, fmt.Sprintf("resolver has [%d] containers", len(containers)))
+	for i, cnt := range containers {
+		var active, inactive, pending = groupBindings(cnt.Bindings())
+
+		out = append(out, fmt.Sprintf("  -> container [%d] has [%d] type binding(s)", i, len(active)))
+		for _, group := range active {
+			out = append(out, fmt.Sprintf("    -> [%s] has [%d] binding(s)", group[0].Abstraction.String(), len(group)))
+
+			for _, info := range group {
+				out = append(out, fmt.Sprintf("     • [%s] %s declared at [%s]", info.Name, func() string {
+					if info.Kind == KindFactory {
+						return "factory"
+					}
 
-	out = append(out, fmt.Sprintf("resolver has [%d] containers", len(r.containers)))
-	for i, c := range r.containers {
-		var cnt *container
-		if cnt, ok = c.(*container); !ok {
-			continue
+					return "instance"
+				}(), info.Caller))
+			}
 		}
 
-		out = append(out, fmt.Sprintf("  -> container [%d] has [%d] type binding(s)", i, len(cnt.bindings)))
+		if pending > 0 {
+			out = append(out, fmt.Sprintf("  -> container [%d] has [%d] pending conditional binding(s)", i, pending))
+		}
 
-		for t, bindingList := range cnt.bindings {
-			out = append(out, fmt.Sprintf("    -> [%s] has [%d] binding(s)", t.String(), len(bindingList)))
+		if len(inactive) == 0 {
+			continue
+		}
 
-			for name, binding := range bindingList {
-				out = append(out, fmt.Sprintf("     • [%s] %s declared at [%s]", name, func() string {
-					if binding.factory != nil {
-						return "factory"
-					}
+		out = append(out, fmt.Sprintf("  -> container [%d] has [%d] inactive type binding(s) for profile(s) [%s]", i, len(inactive), strings.Join(cnt.Profiles(), ",")))
+		for _, group := range inactive {
+			out = append(out, fmt.Sprintf("    -> [%s] has [%d] inactive binding(s)", group[0].Abstraction.String(), len(group)))
 
-					return "instance"
-				}(), binding.caller))
+			for _, info := range group {
+				out = append(out, fmt.Sprintf("     • [%s] inactive, requires %s declared at [%s]", info.Name, info.requirements(), info.Caller))
 			}
 		}
 	}
@@ -98,6 +111,33 @@ func (self *ctx) Visualize() []string {
 	return out
 }
 
+// groupBindings splits bindings by state and groups active and inactive ones by abstraction
+func groupBindings(infos []BindingInfo) (active, inactive [][]BindingInfo, pending int) {
+	var group = func(groups [][]BindingInfo, info BindingInfo) [][]BindingInfo {
+		if n := len(groups); n > 0 && groups[n-1][0].Abstraction == info.Abstraction {
+			groups[n-1] = append(groups[n-1], info)
+			return groups
+		}
+
+		return append(groups, []BindingInfo{info})
+	}
+
+	for _, info := range infos {
+		switch info.State {
+		case StateActive:
+			active = group(active, info)
+
+		case StateInactive:
+			inactive = group(inactive, info)
+
+		case StatePending:
+			pending++
+		}
+	}
+
+	return
+}
+
 // Raw returns raw context.Context
 func (self *ctx) Raw() context.Context {
 	return self.Context
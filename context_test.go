@@ -81,6 +81,118 @@ func (suite *ContextSuite) TestVisualize() {
 	suite.Require().True(strings.Contains(out[5], "di/context_test.go:73"))
 }
 
+func (suite *ContextSuite) TestSetGlobal() {
+	var (
+		container = di.NewContainer()
+		shape     Shape
+	)
+
+	suite.Require().NoError(container.Singleton(newRectangle))
+
+	var restore = di.SetGlobal(container)
+	suite.Require().Equal(container, di.Ctx(context.Background()).Container())
+	suite.Require().NoError(di.Resolve(context.Background(), &shape))
+	suite.Require().IsType(&Rectangle{}, shape)
+
+	restore()
+	suite.Require().NotEqual(container, di.Ctx(context.Background()).Container())
+	suite.Require().EqualError(di.Resolve(context.Background(), &shape), "di: no binding found for di_test.Shape")
+}
+
+func (suite *ContextSuite) TestSetGlobalOutOfOrder() {
+	var (
+		original = di.Ctx(context.Background()).Container()
+		a        = di.NewContainer()
+		b        = di.NewContainer()
+	)
+
+	var restoreA = di.SetGlobal(a)
+	var restoreB = di.SetGlobal(b)
+
+	restoreA()
+	suite.Require().Equal(b, di.Ctx(context.Background()).Container())
+
+	restoreB()
+	suite.Require().Equal(original, di.Ctx(context.Background()).Container())
+
+	restoreB()
+	suite.Require().Equal(original, di.Ctx(context.Background()).Container())
+}
+
+func (suite *ContextSuite) TestWithGlobal() {
+	var shapes = map[string]func() Shape{"circle": newCircle, "rectangle": newRectangle}
+
+	suite.Run("parallel", func() {
+		for name, constructor := range shapes {
+			var constructor = constructor
+
+			suite.T().Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				var container = di.NewContainer()
+				if err := container.Singleton(constructor); err != nil {
+					t.Fatal(err)
+				}
+
+				for i := 0; i < 100; i++ {
+					di.WithGlobal(container, func(ctx context.Context) {
+						var shape Shape
+						if err := di.Resolve(ctx, &shape); err != nil {
+							t.Fatal(err)
+						}
+
+						if shape.GetArea() != constructor().GetArea() {
+							t.Fatalf("unexpected shape %T", shape)
+						}
+					})
+				}
+			})
+		}
+
+		// SetGlobal callers neither see nor affect containers of WithGlobal
+		suite.T().Run("global", func(t *testing.T) {
+			t.Parallel()
+
+			var container = di.NewContainer()
+			if err := container.Singleton(newMySQL); err != nil {
+				t.Fatal(err)
+			}
+
+			for i := 0; i < 100; i++ {
+				var restore = di.SetGlobal(container)
+
+				var shape Shape
+				if err := di.Resolve(context.Background(), &shape); err == nil {
+					t.Fatal("shape of WithGlobal is visible globally")
+				}
+
+				restore()
+			}
+		})
+	})
+
+	var shape Shape
+	suite.Require().EqualError(di.Resolve(context.Background(), &shape), "di: no binding found for di_test.Shape")
+}
+
+func (suite *ContextSuite) TestWithGlobalNested() {
+	var outer, inner = di.NewContainer(), di.NewContainer()
+	suite.Require().NoError(outer.Singleton(newCircle))
+	suite.Require().NoError(inner.Singleton(newRectangle))
+
+	di.WithGlobal(outer, func(ctx context.Context) {
+		di.WithGlobal(inner, func(ctx context.Context) {
+			var shape Shape
+			suite.Require().NoError(di.Resolve(ctx, &shape))
+			suite.Require().IsType(&Rectangle{}, shape)
+		})
+
+		var shape Shape
+		suite.Require().NoError(di.Resolve(ctx, &shape))
+		suite.Require().IsType(&Circle{}, shape)
+	})
+}
+
 func (suite *ContextSuite) TestRaw() {
 	suite.Require().NotNil(suite.context.Raw())
 }
@@ -10,10 +10,59 @@ package di
 import (
 	"context"
 	"reflect"
+	"sync"
+	"sync/atomic"
 )
 
-// globalContext holds a reference to global Container and Resolver
-var globalContext = Ctx(context.Background()).SetContainer(NewContainer())
+var (
+	globalContext atomic.Value // holds a Context with global Container and Resolver
+	globalStack   []Context    // contexts installed by SetGlobal, the last one is the active global
+	globalLock    sync.Mutex   // guards globalStack
+)
+
+func init() {
+	globalStack = []Context{Ctx(context.Background()).SetContainer(NewContainer())}
+	globalContext.Store(globalStack[0])
+}
+
+// global returns a Context with global Container and Resolver
+func global() Context {
+	return globalContext.Load().(Context)
+}
+
+// SetGlobal atomically replaces global Container which is used when context carries no Container.
+// Returned function removes provided Container from globals, so that the one installed before it becomes global again.
+// Restore functions can be called in any order and more than once: the global is always the latest installed Container
+// that is not restored yet.
+func SetGlobal(c Container) (restore func()) {
+	globalLock.Lock()
+	defer globalLock.Unlock()
+
+	var installed = Ctx(context.Background()).SetContainer(c)
+	globalStack = append(globalStack, installed)
+	globalContext.Store(installed)
+
+	return func() {
+		globalLock.Lock()
+		defer globalLock.Unlock()
+
+		for i := len(globalStack) - 1; i > 0; i-- {
+			if globalStack[i] == installed {
+				globalStack = append(globalStack[:i], globalStack[i+1:]...)
+				break
+			}
+		}
+
+		globalContext.Store(globalStack[len(globalStack)-1])
+	}
+}
+
+// WithGlobal calls fn with a context carrying provided Container, package-level functions called with that context
+// use it instead of the global Container. Unlike SetGlobal it doesn't change the process-wide global, so parallel tests
+// using WithGlobal are isolated from each other and from SetGlobal callers. Calls can be nested.
+func WithGlobal(c Container, fn func(ctx context.Context)) {
+	fn(Ctx(context.Background()).SetContainer(c).Raw())
+}
 
 // Singleton binds value(s) returned from constructor as a singleton objects of related types.
 func Singleton(ctx context.Context, constructor any, opts ...Option) error {
@@ -30,6 +79,11 @@ func Implementation(ctx context.Context, implementation any, opts ...Option) err
 	return Ctx(ctx).Container().Implementation(implementation, opts...)
 }
 
+// Value binds a value to its type under provided name.
+func Value(ctx context.Context, name string, value any, opts ...Option) error {
+	return Ctx(ctx).Container().Value(name, value, opts...)
+}
+
 // Reset deletes all the existing bindings and empties the container instance.
 func Reset(ctx context.Context) {
 	Ctx(ctx).Container().Reset()
@@ -52,8 +106,8 @@ func Resolve(ctx context.Context, receiver any, opts ...Option) error {
 
 // Fill takes a struct and resolves the fields with the tag `di:"..."`.
 // Alternatively map[string]Type or []Type can be provided. It will be filled with all available implementations of provided Type.
-func Fill(ctx context.Context, receiver any) error {
-	return Ctx(ctx).Resolver().Fill(receiver)
+func Fill(ctx context.Context, receiver any, opts ...Option) error {
+	return Ctx(ctx).Resolver().Fill(receiver, opts...)
 }
 
 func isError(v reflect.Type) bool {
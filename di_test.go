@@ -3,6 +3,8 @@ package di_test
 import (
 	"context"
 	"time"
+
+	"github.com/HnH/di"
 )
 
 func newCircle() Shape {
@@ -78,3 +80,9 @@ func (m *MongoDB) Construct(context.Context) error {
 func (m *MongoDB) Connect() bool {
 	return true
 }
+
+type providerFunc func(di.Container) error
+
+func (p providerFunc) Provide(c di.Container) error {
+	return p(c)
+}
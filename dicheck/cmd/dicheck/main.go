@@ -0,0 +1,14 @@
+// Command dicheck reports mistakes in github.com/HnH/di usage. It can be run standalone or by go vet:
+//
+//	go install github.com/HnH/di/dicheck/cmd/dicheck@latest
+//	go vet -vettool=$(which dicheck) ./...
+package main
+
+import (
+	"github.com/HnH/di/dicheck"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(dicheck.Analyzer)
+}
@@ -0,0 +1,274 @@
+// Package dicheck implements an analyzer reporting mistakes in di usage that otherwise show up only at runtime:
+// non-pointer receivers passed to Resolve() and Fill(), invalid `di:"..."` struct tags, WithReturn() receivers
+// values returned by a function can't be assigned to, factory methods returning more than one value and
+// WithName() or WithBindResults() names count not matching values returned by a function.
+//
+// Analyzer can be run with `go vet -vettool=$(which dicheck) ./...`, see cmd/dicheck.
+package dicheck
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const diPath = "github.com/HnH/di"
+
+// Analyzer reports di usage mistakes
+var Analyzer = &analysis.Analyzer{
+	Name:     "dicheck",
+	Doc:      "report mistakes in github.com/HnH/di usage",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	var insp = pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil), (*ast.StructType)(nil)}, func(n ast.Node) {
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			checkCall(pass, node)
+
+		case *ast.StructType:
+			checkTags(pass, node)
+		}
+	})
+
+	return nil, nil
+}
+
+// diCall returns a name of a called di function or a Container/Resolver method and its arguments without a context
+func diCall(pass *analysis.Pass, call *ast.CallExpr) (string, []ast.Expr) {
+	var fn, ok = typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != diPath {
+		return "", nil
+	}
+
+	var recv = fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		// package level functions accept context as the first argument
+		if len(call.Args) == 0 {
+			return "", nil
+		}
+
+		return fn.Name(), call.Args[1:]
+	}
+
+	var named, isNamed = recv.Type().(*types.Named)
+	if !isNamed || named.Obj().Name() != "Container" && named.Obj().Name() != "Resolver" {
+		return "", nil
+	}
+
+	return fn.Name(), call.Args
+}
+
+// optionArgs returns arguments of a di option constructor with provided name if expression is its call
+func optionArgs(pass *analysis.Pass, expr ast.Expr, name string) ([]ast.Expr, bool) {
+	var call, ok = astutil.Unparen(expr).(*ast.CallExpr)
+	if !ok || call.Ellipsis.IsValid() {
+		return nil, false
+	}
+
+	var fn, isFunc = typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !isFunc || fn.Pkg() == nil || fn.Pkg().Path() != diPath || fn.Name() != name {
+		return nil, false
+	}
+
+	return call.Args, true
+}
+
+func checkCall(pass *analysis.Pass, call *ast.CallExpr) {
+	var name, args = diCall(pass, call)
+	if name == "" || len(args) == 0 {
+		return
+	}
+
+	switch name {
+	case "Resolve", "Fill":
+		checkReceiver(pass, name, args[0])
+
+	case "Factory":
+		checkFactory(pass, args[0])
+
+	case "Singleton":
+		if !call.Ellipsis.IsValid() {
+			checkNames(pass, args[0], args[1:], "WithName")
+		}
+
+	case "Call":
+		if !call.Ellipsis.IsValid() {
+			checkReturns(pass, args[0], args[1:])
+			checkNames(pass, args[0], args[1:], "WithBindResults")
+		}
+	}
+}
+
+// checkReceiver reports receivers that are not pointers
+func checkReceiver(pass *analysis.Pass, name string, arg ast.Expr) {
+	var t = pass.TypesInfo.TypeOf(arg)
+	if t == nil || types.IsInterface(t) {
+		return
+	}
+
+	if _, ok := t.Underlying().(*types.Pointer); !ok {
+		pass.Reportf(arg.Pos(), "di: %s receiver must be a pointer, got %s", name, typeString(t))
+	}
+}
+
+// typeString formats a type the way reflect does, qualifying it with a package name
+func typeString(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string { return p.Name() })
+}
+
+// signature returns a signature of a function expression
+func signature(pass *analysis.Pass, expr ast.Expr) *types.Signature {
+	var t = pass.TypesInfo.TypeOf(expr)
+	if t == nil {
+		return nil
+	}
+
+	var sig, _ = t.Underlying().(*types.Signature)
+
+	return sig
+}
+
+// outputs returns returned values of a function without a trailing error
+func outputs(sig *types.Signature) []types.Type {
+	var out = make([]types.Type, 0, sig.Results().Len())
+	for i := 0; i < sig.Results().Len(); i++ {
+		var t = sig.Results().At(i).Type()
+		if i == sig.Results().Len()-1 && types.Identical(t, types.Universe.Lookup("error").Type()) {
+			break
+		}
+
+		out = append(out, t)
+	}
+
+	return out
+}
+
+// checkFactory reports factory methods that don't return exactly one value and optionally one error
+func checkFactory(pass *analysis.Pass, arg ast.Expr) {
+	var sig = signature(pass, arg)
+	if sig == nil {
+		return
+	}
+
+	if n := len(outputs(sig)); n != 1 || sig.Results().Len() > 2 {
+		pass.Reportf(arg.Pos(), "di: factory resolvers must return exactly one value and optionally one error, got %d", n)
+	}
+}
+
+// checkNames reports WithName() or WithBindResults() names count not matching number of values returned by a function
+func checkNames(pass *analysis.Pass, arg ast.Expr, opts []ast.Expr, option string) {
+	var sig = signature(pass, arg)
+	if sig == nil {
+		return
+	}
+
+	var n = len(outputs(sig))
+	for _, opt := range opts {
+		var names, ok = optionArgs(pass, opt, option)
+		if ok && n > 1 && len(names) > 1 && len(names) != n {
+			pass.Reportf(opt.Pos(), "di: the function returns %d values, but %d names are provided", n, len(names))
+		}
+	}
+}
+
+// checkReturns reports WithReturn() receivers not matching values returned by a function
+func checkReturns(pass *analysis.Pass, arg ast.Expr, opts []ast.Expr) {
+	var sig = signature(pass, arg)
+	if sig == nil {
+		return
+	}
+
+	var out = outputs(sig)
+	for _, opt := range opts {
+		var returns, ok = optionArgs(pass, opt, "WithReturn")
+		if !ok {
+			continue
+		}
+
+		if len(returns) != len(out) {
+			pass.Reportf(opt.Pos(), "di: cannot assign %d returned values to %d receivers", len(out), len(returns))
+			continue
+		}
+
+		for i, ret := range returns {
+			// nil placeholders skip returned values
+			if pass.TypesInfo.Types[ret].IsNil() {
+				continue
+			}
+
+			var t = pass.TypesInfo.TypeOf(ret)
+			if t == nil || types.IsInterface(t) {
+				continue
+			}
+
+			var ptr, isPtr = t.Underlying().(*types.Pointer)
+			if !isPtr || !types.AssignableTo(out[i], ptr.Elem()) {
+				pass.Reportf(ret.Pos(), "di: cannot assign returned value of type %s to %s",
+					typeString(out[i]), typeString(t))
+			}
+		}
+	}
+}
+
+// checkTags reports `di` struct tags that Resolver.Fill() rejects
+func checkTags(pass *analysis.Pass, st *ast.StructType) {
+	for _, field := range st.Fields.List {
+		if field.Tag == nil {
+			continue
+		}
+
+		var raw, err = strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			continue
+		}
+
+		var tag, ok = reflect.StructTag(raw).Lookup("di")
+		if !ok || validTag(tag) {
+			continue
+		}
+
+		pass.Reportf(field.Tag.Pos(), "di: invalid struct tag %q", tag)
+	}
+}
+
+// validTag mirrors tags accepted by Resolver.Fill()
+func validTag(tag string) bool {
+	// omitempty must be the last option
+	if tag = strings.TrimSuffix(tag, ",omitempty"); strings.Contains(tag, ",omitempty") {
+		return false
+	}
+
+	switch {
+	case tag == "type", tag == "name", tag == "recursive":
+		return true
+
+	case strings.HasPrefix(tag, "config="):
+		var key = strings.TrimPrefix(tag, "config=")
+		if idx := strings.Index(key, ",default="); idx != -1 {
+			key = key[:idx]
+		}
+
+		return key != ""
+
+	case strings.HasPrefix(tag, "secret="):
+		return strings.TrimPrefix(tag, "secret=") != ""
+
+	case strings.HasPrefix(tag, "name="):
+		return strings.TrimPrefix(tag, "name=") != ""
+	}
+
+	return false
+}
@@ -0,0 +1,12 @@
+package dicheck_test
+
+import (
+	"testing"
+
+	"github.com/HnH/di/dicheck"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), dicheck.Analyzer, "a")
+}
@@ -0,0 +1,72 @@
+package a
+
+import (
+	"context"
+
+	"github.com/HnH/di"
+)
+
+type Shape interface{ Area() int }
+
+type Circle struct{}
+
+func (Circle) Area() int { return 1 }
+
+type Target struct {
+	A Shape `di:"type"`
+	B Shape `di:"name,omitempty"`
+	C Shape `di:"recursive"`
+	D int   `di:"config=port,default=80"`
+	E int   `di:"secret=token"`
+	F Shape `di:"types"`             // want `di: invalid struct tag "types"`
+	G int   `di:"config=,default=1"` // want `di: invalid struct tag "config=,default=1"`
+	H Shape `json:"h" di:"secret="`  // want `di: invalid struct tag "secret="`
+	I Shape `json:"i"`
+	J int   `di:"name=http.port"`
+	K int   `di:"name="`                            // want `di: invalid struct tag "name="`
+	L int   `di:"config=port,omitempty,default=80"` // want `di: invalid struct tag "config=port,omitempty,default=80"`
+}
+
+func newPair() (Shape, *Circle, error) { return nil, nil, nil }
+
+func resolve(ctx context.Context, c di.Container, r di.Resolver) {
+	var (
+		shape  Shape
+		circle *Circle
+		target Target
+		any    interface{} = &shape
+		names              = []string{"a", "b", "c"}
+	)
+
+	_ = r.Resolve(&shape)
+	_ = r.Resolve(any)
+	_ = r.Resolve(shape)                          // shape is an interface, can't be checked statically
+	_ = r.Resolve(target)                         // want `di: Resolve receiver must be a pointer, got a.Target`
+	_ = di.Resolve(ctx, target, di.WithName("x")) // want `di: Resolve receiver must be a pointer, got a.Target`
+	_ = di.Fill(ctx, target)                      // want `di: Fill receiver must be a pointer, got a.Target`
+	_ = r.Fill(&target)
+
+	_ = c.Factory(func() Shape { return nil })
+	_ = c.Factory(func() (Shape, error) { return nil, nil })
+	_ = c.Factory(newPair)                                         // want `di: factory resolvers must return exactly one value and optionally one error, got 2`
+	_ = di.Factory(ctx, func() (Shape, Shape) { return nil, nil }) // want `di: factory resolvers must return exactly one value and optionally one error, got 2`
+	_ = c.Factory(func() error { return nil })                     // want `di: factory resolvers must return exactly one value and optionally one error, got 0`
+
+	_ = c.Singleton(newPair, di.WithName("a", "b"))
+	_ = c.Singleton(newPair, di.WithName("a"))
+	_ = c.Singleton(newPair, di.WithName(names...))
+	_ = c.Singleton(newPair, di.WithName("a", "b", "c"))       // want `di: the function returns 2 values, but 3 names are provided`
+	_ = di.Singleton(ctx, newPair, di.WithName("a", "b", "c")) // want `di: the function returns 2 values, but 3 names are provided`
+
+	_ = r.Call(newPair, di.WithReturn(&shape, &circle))
+	_ = r.Call(newPair, di.WithReturn(&shape))              // want `di: cannot assign 2 returned values to 1 receivers`
+	_ = r.Call(newPair, di.WithReturn(&circle, &circle))    // want `di: cannot assign returned value of type a.Shape to \*\*a.Circle`
+	_ = di.Call(ctx, newPair, di.WithReturn(shape, circle)) // want `di: cannot assign returned value of type \*a.Circle to \*a.Circle`
+	_ = r.Call(newPair, di.WithReturn(any, &circle))
+	_ = r.Call(newPair, di.WithReturn(&shape, &shape))
+	_ = r.Call(newPair, di.WithReturn(nil, &circle))
+	_ = r.Call(newPair, di.WithReturn(&circle, nil)) // want `di: cannot assign returned value of type a.Shape to \*\*a.Circle`
+
+	_ = r.Call(newPair, di.WithBindResults("a", "b"))
+	_ = r.Call(newPair, di.WithBindResults("a", "b", "c")) // want `di: the function returns 2 values, but 3 names are provided`
+}
@@ -0,0 +1,37 @@
+// Package di is a stub of github.com/HnH/di API used by analyzer tests
+package di
+
+import "context"
+
+type Option func(any)
+
+type Container interface {
+	Singleton(constructor any, opts ...Option) error
+	Factory(constructor any, opts ...Option) error
+}
+
+type Resolver interface {
+	Resolve(receiver any, opts ...Option) error
+	Call(function any, opts ...Option) error
+	Fill(receiver any, opts ...Option) error
+}
+
+func NewContainer(opts ...Option) Container { return nil }
+
+func NewResolver(containers ...Container) Resolver { return nil }
+
+func Singleton(ctx context.Context, constructor any, opts ...Option) error { return nil }
+
+func Factory(ctx context.Context, constructor any, opts ...Option) error { return nil }
+
+func Call(ctx context.Context, function any, opts ...Option) error { return nil }
+
+func Resolve(ctx context.Context, receiver any, opts ...Option) error { return nil }
+
+func Fill(ctx context.Context, receiver any, opts ...Option) error { return nil }
+
+func WithName(names ...string) Option { return nil }
+
+func WithReturn(returns ...any) Option { return nil }
+
+func WithBindResults(names ...string) Option { return nil }
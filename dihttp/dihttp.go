@@ -0,0 +1,187 @@
+// Package dihttp serves a debug view of DI containers over HTTP, similarly to net/http/pprof.
+//
+// The handler is usually mounted at /debug/di:
+//
+//	dihttp.Register(http.DefaultServeMux, resolver)
+//
+// It renders an HTML page by default and JSON if `format=json` query parameter is set or JSON is accepted by the client.
+//
+// Liveness and readiness handlers backed by di.HealthCheck are mounted with RegisterHealth.
+package dihttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/HnH/di"
+)
+
+// Path is a conventional path the handler is mounted at
+const Path = "/debug/di"
+
+// Report describes containers of a resolver
+type Report struct {
+	Containers []Container `json:"containers"`
+}
+
+// Container describes a single container
+type Container struct {
+	ID       uint64    `json:"id"`
+	Profiles []string  `json:"profiles"`
+	Bindings []Binding `json:"bindings"`
+}
+
+// Binding describes a single binding, see di.BindingInfo
+type Binding struct {
+	Type         string       `json:"type"`
+	Name         string       `json:"name"`
+	Kind         string       `json:"kind"`
+	State        string       `json:"state"`
+	Instantiated bool         `json:"instantiated"`
+	Constructor  string       `json:"constructor,omitempty"`
+	Caller       string       `json:"caller"`
+	Fill         bool         `json:"fill"`
+	Setters      []string     `json:"setters,omitempty"`
+	Profiles     []string     `json:"profiles,omitempty"`
+	Conditions   []string     `json:"conditions,omitempty"`
+	Dependencies []Dependency `json:"dependencies,omitempty"`
+	Resolutions  uint64       `json:"resolutions"`
+}
+
+// Dependency is an edge between a binding and an abstraction its constructor requires.
+// Container is an ID of the first container that has an active default binding of the abstraction, 0 if there is none.
+type Dependency struct {
+	Type      string `json:"type"`
+	Container uint64 `json:"container,omitempty"`
+}
+
+// NewReport describes all containers of a resolver in resolution order
+func NewReport(r di.Resolver) Report {
+	var (
+		out   = Report{Containers: make([]Container, 0)}
+		infos = make([][]di.BindingInfo, 0)
+		owner = make(map[reflect.Type]uint64)
+	)
+
+	for _, c := range r.Containers() {
+		var list = c.Bindings()
+		for _, info := range list {
+			if _, ok := owner[info.Abstraction]; !ok && info.State == di.StateActive && info.Name == di.DefaultBindName {
+				owner[info.Abstraction] = info.Container
+			}
+		}
+
+		infos = append(infos, list)
+		out.Containers = append(out.Containers, Container{ID: c.ID(), Profiles: c.Profiles(), Bindings: make([]Binding, 0, len(list))})
+	}
+
+	for i, list := range infos {
+		for _, info := range list {
+			var bnd = Binding{
+				Type:         info.Abstraction.String(),
+				Name:         info.Name,
+				Kind:         string(info.Kind),
+				State:        string(info.State),
+				Instantiated: info.Instantiated,
+				Caller:       info.Caller,
+				Fill:         info.Fill,
+				Setters:      info.Setters,
+				Profiles:     info.Profiles,
+				Conditions:   info.Conditions,
+				Resolutions:  info.Resolutions,
+			}
+
+			if info.Constructor != nil {
+				bnd.Constructor = info.Constructor.String()
+			}
+
+			for _, dep := range info.Dependencies {
+				bnd.Dependencies = append(bnd.Dependencies, Dependency{Type: dep.String(), Container: owner[dep]})
+			}
+
+			out.Containers[i].Bindings = append(out.Containers[i].Bindings, bnd)
+		}
+	}
+
+	return out
+}
+
+// Handler returns a handler rendering bindings of resolver containers
+func Handler(r di.Resolver) http.Handler {
+	return handler{resolver: r}
+}
+
+// Register mounts a handler at Path
+func Register(mux *http.ServeMux, r di.Resolver) {
+	mux.Handle(Path, Handler(r))
+}
+
+type handler struct {
+	resolver di.Resolver
+}
+
+// ServeHTTP implements http.Handler interface
+func (self handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var report = NewReport(self.resolver)
+
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	if req.URL.Query().Get("format") == "json" || strings.Contains(req.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json")
+
+		var enc = json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		if err := enc.Encode(report); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if err := page.Execute(w, report); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// anchor builds an HTML element id of an abstraction bound in a container
+func anchor(container uint64, typ string) string {
+	return fmt.Sprintf("c%d-%s", container, strings.Join(strings.Fields(typ), "_"))
+}
+
+var page = template.Must(template.New("di").Funcs(template.FuncMap{"anchor": anchor}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<title>/debug/di</title>
+<style>
+body { font-family: monospace; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 2px 6px; text-align: left; vertical-align: top; }
+tr.inactive, tr.pending { color: #999; }
+</style>
+</head>
+<body>
+<p><a href="?format=json">json</a></p>
+{{range .Containers}}{{$id := .ID}}
+<h2>container {{.ID}}</h2>
+<p>profiles: {{range $i, $p := .Profiles}}{{if $i}}, {{end}}{{$p}}{{else}}none{{end}}</p>
+<table>
+<tr><th>type</th><th>name</th><th>kind</th><th>state</th><th>instantiated</th><th>resolutions</th><th>constructor</th><th>dependencies</th><th>caller</th></tr>
+{{range .Bindings}}<tr class="{{.State}}"{{if and (eq .State "active") (eq .Name "default")}} id="{{anchor $id .Type}}"{{end}}>
+<td>{{.Type}}</td><td>{{.Name}}</td><td>{{.Kind}}{{if .Fill}}, fill{{end}}</td>
+<td>{{.State}}{{range .Profiles}}<br>profile {{.}}{{end}}{{range .Conditions}}<br>{{.}}{{end}}</td>
+<td>{{.Instantiated}}</td><td>{{.Resolutions}}</td><td>{{.Constructor}}</td>
+<td>{{range .Dependencies}}{{if .Container}}<a href="#{{anchor .Container .Type}}">{{.Type}}</a>{{else}}{{.Type}} (unbound){{end}}<br>{{end}}</td>
+<td>{{.Caller}}</td>
+</tr>
+{{end}}</table>
+{{end}}
+</body>
+</html>
+`))
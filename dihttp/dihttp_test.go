@@ -0,0 +1,130 @@
+package dihttp_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/HnH/di"
+	"github.com/HnH/di/dihttp"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestDihttpSuite(t *testing.T) {
+	suite.Run(t, new(DihttpSuite))
+}
+
+type DihttpSuite struct {
+	app, lib di.Container
+	resolver di.Resolver
+
+	suite.Suite
+}
+
+type Config struct{}
+
+type Database interface {
+	Ping() error
+}
+
+type MySQL struct{}
+
+func (MySQL) Ping() error { return nil }
+
+type Service struct{}
+
+func (suite *DihttpSuite) SetupTest() {
+	suite.app = di.NewContainer(di.WithProfiles("prod"))
+	suite.lib = di.NewContainer()
+	suite.resolver = di.NewResolver(suite.app, suite.lib)
+
+	suite.Require().NoError(suite.lib.Implementation(&Config{}))
+	suite.Require().NoError(suite.app.Factory(func(*Config, di.Optional[Database]) *Service { return &Service{} }))
+	suite.Require().NoError(suite.app.Singleton(func() Database { return MySQL{} }, di.WhenProfile("test")))
+	suite.Require().NoError(suite.app.Factory(func(*Config) Database { return MySQL{} }, di.WithName("<script>")))
+}
+
+func (suite *DihttpSuite) serve(target string, header http.Header) *httptest.ResponseRecorder {
+	var (
+		mux = http.NewServeMux()
+		rec = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, target, nil)
+	)
+
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	dihttp.Register(mux, suite.resolver)
+	mux.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func (suite *DihttpSuite) TestJSON() {
+	var svc *Service
+	suite.Require().NoError(suite.resolver.Resolve(&svc))
+	suite.Require().NoError(suite.resolver.Resolve(&svc))
+
+	var rec = suite.serve(dihttp.Path+"?format=json", nil)
+	suite.Require().Equal(http.StatusOK, rec.Code)
+	suite.Require().Equal("application/json", rec.Header().Get("Content-Type"))
+
+	var report dihttp.Report
+	suite.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &report))
+	suite.Require().Len(report.Containers, 2)
+
+	var app, lib = report.Containers[0], report.Containers[1]
+	suite.Require().Equal(suite.app.ID(), app.ID)
+	suite.Require().Equal([]string{"prod"}, app.Profiles)
+	suite.Require().Len(app.Bindings, 3)
+
+	var service = app.Bindings[0]
+	suite.Require().Equal("*dihttp_test.Service", service.Type)
+	suite.Require().Equal(di.DefaultBindName, service.Name)
+	suite.Require().Equal("factory", service.Kind)
+	suite.Require().Equal("active", service.State)
+	suite.Require().False(service.Instantiated)
+	suite.Require().Equal(uint64(2), service.Resolutions)
+	suite.Require().Equal("func(*dihttp_test.Config, di.Optional[github.com/HnH/di/dihttp_test.Database]) *dihttp_test.Service", service.Constructor)
+	suite.Require().Contains(service.Caller, "dihttp_test.go")
+	suite.Require().Equal([]dihttp.Dependency{
+		{Type: "*dihttp_test.Config", Container: suite.lib.ID()},
+		{Type: "dihttp_test.Database"},
+	}, service.Dependencies)
+
+	suite.Require().Equal("<script>", app.Bindings[1].Name)
+	suite.Require().Equal("inactive", app.Bindings[2].State)
+	suite.Require().Equal([]string{"test"}, app.Bindings[2].Profiles)
+
+	suite.Require().Len(lib.Bindings, 1)
+	suite.Require().Equal("implementation", lib.Bindings[0].Kind)
+	suite.Require().True(lib.Bindings[0].Instantiated)
+	suite.Require().Equal(uint64(2), lib.Bindings[0].Resolutions)
+
+	rec = suite.serve(dihttp.Path, http.Header{"Accept": {"application/json"}})
+	suite.Require().Equal("application/json", rec.Header().Get("Content-Type"))
+}
+
+func (suite *DihttpSuite) TestHTML() {
+	var rec = suite.serve(dihttp.Path, nil)
+	suite.Require().Equal(http.StatusOK, rec.Code)
+	suite.Require().Equal("text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+
+	var body = rec.Body.String()
+	suite.Require().Contains(body, "<h2>container ")
+	suite.Require().Contains(body, "*dihttp_test.Service")
+	suite.Require().Contains(body, "&lt;script&gt;")
+	suite.Require().NotContains(body, "<script>")
+	suite.Require().Contains(body, `<a href="#c`)
+	suite.Require().Contains(body, "dihttp_test.Database (unbound)")
+	suite.Require().Equal(2, strings.Count(body, "<table>"))
+}
+
+func (suite *DihttpSuite) TestEmpty() {
+	var rec = httptest.NewRecorder()
+	dihttp.Handler(di.NewResolver()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?format=json", nil))
+	suite.Require().JSONEq(`{"containers":[]}`, rec.Body.String())
+}
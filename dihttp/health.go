@@ -0,0 +1,58 @@
+package dihttp
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/HnH/di"
+)
+
+const (
+	// LivenessPath is a conventional path the liveness handler is mounted at
+	LivenessPath = "/livez"
+	// ReadinessPath is a conventional path the readiness handler is mounted at
+	ReadinessPath = "/readyz"
+)
+
+// Liveness returns a handler that reports the process is alive. It doesn't run health checks, so that a failing
+// dependency doesn't get the process restarted.
+func Liveness() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]bool{"healthy": true})
+	})
+}
+
+// Readiness returns a handler that runs health checks and renders di.HealthReport,
+// responds with 503 Service Unavailable if any of the checks fail
+func Readiness(h *di.HealthCheck) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var (
+			report = h.Check(req.Context())
+			status = http.StatusOK
+		)
+
+		if !report.Healthy {
+			status = http.StatusServiceUnavailable
+		}
+
+		writeJSON(w, status, report)
+	})
+}
+
+// RegisterHealth mounts liveness and readiness handlers at LivenessPath and ReadinessPath
+func RegisterHealth(mux *http.ServeMux, h *di.HealthCheck) {
+	mux.Handle(LivenessPath, Liveness())
+	mux.Handle(ReadinessPath, Readiness(h))
+}
+
+// writeJSON writes an indented JSON response
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+
+	var enc = json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
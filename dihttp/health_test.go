@@ -0,0 +1,75 @@
+package dihttp_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HnH/di"
+	"github.com/HnH/di/dihttp"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestHealthSuite(t *testing.T) {
+	suite.Run(t, new(HealthSuite))
+}
+
+type HealthSuite struct {
+	suite.Suite
+}
+
+// Cache is a di.HealthChecker
+type Cache struct {
+	err error
+}
+
+func (c *Cache) HealthCheck(context.Context) error { return c.err }
+
+func (suite *HealthSuite) serve(c di.Container, target string) *httptest.ResponseRecorder {
+	var (
+		mux = http.NewServeMux()
+		rec = httptest.NewRecorder()
+	)
+
+	dihttp.RegisterHealth(mux, di.Health(di.NewResolver(c)))
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+
+	return rec
+}
+
+func (suite *HealthSuite) TestReadiness() {
+	var (
+		c     = di.NewContainer()
+		cache = &Cache{}
+	)
+
+	suite.Require().NoError(c.Implementation(cache))
+
+	var rec = suite.serve(c, dihttp.ReadinessPath)
+	suite.Require().Equal(http.StatusOK, rec.Code)
+	suite.Require().Equal("application/json", rec.Header().Get("Content-Type"))
+
+	var report di.HealthReport
+	suite.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &report))
+	suite.Require().True(report.Healthy)
+	suite.Require().True(report.Checks["*dihttp_test.Cache [default]"].Healthy)
+
+	cache.err = errors.New("connection refused")
+	rec = suite.serve(c, dihttp.ReadinessPath)
+	suite.Require().Equal(http.StatusServiceUnavailable, rec.Code)
+	suite.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &report))
+	suite.Require().False(report.Healthy)
+	suite.Require().Equal("connection refused", report.Checks["*dihttp_test.Cache [default]"].Error)
+}
+
+func (suite *HealthSuite) TestLiveness() {
+	var c = di.NewContainer()
+	suite.Require().NoError(c.Implementation(&Cache{err: errors.New("connection refused")}))
+
+	var rec = suite.serve(c, dihttp.LivenessPath)
+	suite.Require().Equal(http.StatusOK, rec.Code)
+	suite.Require().JSONEq(`{"healthy": true}`, rec.Body.String())
+}
@@ -0,0 +1,148 @@
+// Package ditest implements helpers for testing DI wiring: isolated containers, temporary overrides of bindings
+// and assertions on resolvability and the container binding graph.
+package ditest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/HnH/di"
+)
+
+// update instructs AssertGolden to rewrite golden files instead of comparing against them
+var update = flag.Bool("ditest.update", false, "update ditest golden files")
+
+// New creates an isolated container, fills it with provided providers and builds conditional bindings.
+// Test fails immediately if any of the providers or the build returns an error.
+func New(t testing.TB, providers ...di.Provider) di.Container {
+	t.Helper()
+
+	var c = di.NewContainer()
+	for _, p := range providers {
+		if err := p.Provide(c); err != nil {
+			t.Fatalf("ditest: provider %T failed: %v", p, err)
+			return c
+		}
+	}
+
+	if err := c.Build(); err != nil {
+		t.Fatalf("ditest: container build failed: %v", err)
+	}
+
+	return c
+}
+
+// Override binds impl as a singleton of abstraction T for the duration of a test.
+// The container is restored to its state before the override on test cleanup, so previous bindings under the same names
+// come back and bindings made after the override are rolled back as well.
+func Override[T any](t testing.TB, c di.Container, impl T, opts ...di.Option) {
+	t.Helper()
+
+	var snapshot = c.Snapshot()
+	if err := c.Singleton(func() T { return impl }, opts...); err != nil {
+		t.Fatalf("ditest: unable to override %s: %v", reflect.TypeOf((*T)(nil)).Elem().String(), err)
+		return
+	}
+
+	t.Cleanup(func() { c.Restore(snapshot) })
+}
+
+// AssertResolvable checks that T can be resolved with provided name and returns resolved instance
+func AssertResolvable[T any](t testing.TB, r di.Resolver, name ...string) (out T) {
+	t.Helper()
+
+	if err := r.Resolve(&out, di.WithName(name...)); err != nil {
+		t.Errorf("ditest: %s is not resolvable: %v", reflect.TypeOf((*T)(nil)).Elem().String(), err)
+	}
+
+	return
+}
+
+// AssertBound checks that container has bindings with all provided names (default name if none) for an abstraction.
+// Abstraction should be passed as a pointer, e.g. (*Logger)(nil).
+func AssertBound(t testing.TB, c di.Container, abstraction any, names ...string) bool {
+	t.Helper()
+
+	var ref = reflect.TypeOf(abstraction)
+	if ref == nil || ref.Kind() != reflect.Ptr {
+		t.Errorf("ditest: abstraction must be a pointer, got %T", abstraction)
+		return false
+	}
+
+	if len(names) == 0 {
+		names = []string{di.DefaultBindName}
+	}
+
+	var list, _ = c.ListBindings(ref.Elem())
+	for _, name := range names {
+		if _, ok := list[name]; !ok {
+			t.Errorf("ditest: %s is not bound with name %s", ref.Elem().String(), name)
+			return false
+		}
+	}
+
+	return true
+}
+
+// AssertGolden compares container binding graph with a golden file. Run tests with `-ditest.update` flag to rewrite golden files.
+func AssertGolden(t testing.TB, c di.Container, path string) {
+	t.Helper()
+
+	var actual = Graph(c)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("ditest: unable to create golden file directory: %v", err)
+			return
+		}
+
+		if err := os.WriteFile(path, []byte(actual), 0o644); err != nil {
+			t.Fatalf("ditest: unable to write golden file: %v", err)
+		}
+
+		return
+	}
+
+	var expected, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ditest: unable to read golden file, run tests with -ditest.update to create it: %v", err)
+		return
+	}
+
+	if string(expected) != actual {
+		t.Errorf("ditest: binding graph does not match golden file %s\n--- expected\n%s\n--- actual\n%s", path, expected, actual)
+	}
+}
+
+// Graph renders container bindings in a stable form suitable for comparison: one binding per line in declaration order,
+// declaration sites are omitted.
+func Graph(c di.Container) string {
+	var sb strings.Builder
+	for _, info := range c.Bindings() {
+		fmt.Fprintf(&sb, "%s %s [%s] %s", info.State, info.Abstraction.String(), info.Name, info.Kind)
+
+		if info.Constructor != nil {
+			fmt.Fprintf(&sb, " %s", info.Constructor.String())
+		}
+
+		if info.Fill {
+			sb.WriteString(" fill")
+		}
+
+		if len(info.Profiles) > 0 {
+			fmt.Fprintf(&sb, " profile(s) [%s]", strings.Join(info.Profiles, ","))
+		}
+
+		for _, cond := range info.Conditions {
+			fmt.Fprintf(&sb, " %s", cond)
+		}
+
+		sb.WriteByte('\n')
+	}
+
+	return sb.String()
+}
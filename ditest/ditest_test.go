@@ -0,0 +1,186 @@
+package ditest_test
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/HnH/di"
+	"github.com/HnH/di/ditest"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestDitestSuite(t *testing.T) {
+	suite.Run(t, new(DitestSuite))
+}
+
+type DitestSuite struct {
+	suite.Suite
+}
+
+type Shape interface {
+	Area() int
+}
+
+type Circle struct{}
+
+func (Circle) Area() int { return 314 }
+
+type Square struct{}
+
+func (Square) Area() int { return 100 }
+
+type providerFunc func(di.Container) error
+
+func (p providerFunc) Provide(c di.Container) error {
+	return p(c)
+}
+
+var shapes = providerFunc(func(c di.Container) error {
+	if err := c.Singleton(func() Shape { return Circle{} }); err != nil {
+		return err
+	}
+
+	if err := c.Factory(func() Shape { return Square{} }, di.WithName("square")); err != nil {
+		return err
+	}
+
+	return c.Singleton(func() *Square { return &Square{} }, di.IfMissing[*Square]())
+})
+
+// recorder is a testing.TB that records failures instead of failing a test
+type recorder struct {
+	testing.TB
+
+	errors   []string
+	fatal    bool
+	cleanups []func()
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+	r.fatal = true
+}
+
+func (r *recorder) Cleanup(f func()) {
+	r.cleanups = append(r.cleanups, f)
+}
+
+func (r *recorder) cleanup() {
+	for i := len(r.cleanups) - 1; i >= 0; i-- {
+		r.cleanups[i]()
+	}
+}
+
+func (suite *DitestSuite) TestNew() {
+	var c = ditest.New(suite.T(), shapes)
+	ditest.AssertBound(suite.T(), c, (*Shape)(nil), di.DefaultBindName, "square")
+	ditest.AssertBound(suite.T(), c, (**Square)(nil))
+
+	var rec = new(recorder)
+	ditest.New(rec, shapes, providerFunc(func(di.Container) error { return errors.New("dummy error") }))
+	suite.Require().True(rec.fatal)
+	suite.Require().Equal([]string{"ditest: provider ditest_test.providerFunc failed: dummy error"}, rec.errors)
+
+	rec = new(recorder)
+	ditest.New(rec, providerFunc(func(c di.Container) error {
+		return c.Singleton(func() (Shape, error) { return nil, errors.New("build error") }, di.IfMissing[Shape]())
+	}))
+	suite.Require().True(rec.fatal)
+	suite.Require().Equal([]string{"ditest: container build failed: build error"}, rec.errors)
+}
+
+func (suite *DitestSuite) TestOverride() {
+	var (
+		c   = ditest.New(suite.T(), shapes)
+		r   = di.NewResolver(c)
+		rec = new(recorder)
+	)
+
+	ditest.Override[Shape](rec, c, Square{})
+	ditest.Override[Shape](rec, c, Circle{}, di.WithName("square", "other"))
+	suite.Require().Empty(rec.errors)
+
+	suite.Require().Equal(Square{}, ditest.AssertResolvable[Shape](suite.T(), r))
+	suite.Require().Equal(Circle{}, ditest.AssertResolvable[Shape](suite.T(), r, "square"))
+	suite.Require().Equal(Circle{}, ditest.AssertResolvable[Shape](suite.T(), r, "other"))
+
+	rec.cleanup()
+
+	suite.Require().Equal(Circle{}, ditest.AssertResolvable[Shape](suite.T(), r))
+	suite.Require().Equal(Square{}, ditest.AssertResolvable[Shape](suite.T(), r, "square"))
+
+	rec = new(recorder)
+	suite.Require().Nil(ditest.AssertResolvable[Shape](rec, r, "other"))
+	suite.Require().Equal([]string{"ditest: ditest_test.Shape is not resolvable: di: no binding found for ditest_test.Shape"}, rec.errors)
+
+	rec = new(recorder)
+	ditest.Override[Shape](rec, c, Circle{}, di.WithName("a", "b", "c"), di.WithFill())
+	suite.Require().True(rec.fatal)
+	suite.Require().Equal([]string{"ditest: unable to override ditest_test.Shape: di: receiver is not a pointer: struct"}, rec.errors)
+}
+
+func (suite *DitestSuite) TestAssertBound() {
+	var (
+		c   = ditest.New(suite.T(), shapes)
+		rec = new(recorder)
+	)
+
+	suite.Require().True(ditest.AssertBound(rec, c, (*Shape)(nil)))
+	suite.Require().False(ditest.AssertBound(rec, c, (*Shape)(nil), "square", "circle"))
+	suite.Require().False(ditest.AssertBound(rec, c, Circle{}))
+	suite.Require().False(ditest.AssertBound(rec, c, nil))
+	suite.Require().Equal([]string{
+		"ditest: ditest_test.Shape is not bound with name circle",
+		"ditest: abstraction must be a pointer, got ditest_test.Circle",
+		"ditest: abstraction must be a pointer, got <nil>",
+	}, rec.errors)
+}
+
+func (suite *DitestSuite) TestAssertGolden() {
+	var c = ditest.New(suite.T(), shapes)
+	ditest.AssertGolden(suite.T(), c, filepath.Join("testdata", "shapes.golden"))
+
+	var rec = new(recorder)
+	ditest.AssertGolden(rec, ditest.New(suite.T()), filepath.Join("testdata", "shapes.golden"))
+	suite.Require().Len(rec.errors, 1)
+	suite.Require().Contains(rec.errors[0], "ditest: binding graph does not match golden file testdata/shapes.golden")
+
+	rec = new(recorder)
+	ditest.AssertGolden(rec, c, filepath.Join(suite.T().TempDir(), "missing.golden"))
+	suite.Require().True(rec.fatal)
+
+	suite.Require().NoError(flag.Set("ditest.update", "true"))
+	defer func() { suite.Require().NoError(flag.Set("ditest.update", "false")) }()
+
+	var path = filepath.Join(suite.T().TempDir(), "nested", "new.golden")
+	ditest.AssertGolden(suite.T(), c, path)
+
+	var raw, err = os.ReadFile(path)
+	suite.Require().NoError(err)
+	suite.Require().Equal(ditest.Graph(c), string(raw))
+}
+
+func (suite *DitestSuite) TestGraph() {
+	var c = ditest.New(suite.T(), shapes)
+	suite.Require().NoError(c.Singleton(func() Shape { return Circle{} }, di.WhenProfile("prod")))
+	suite.Require().NoError(c.Implementation(&Circle{}, di.IfPresent[Circle]()))
+	suite.Require().NoError(c.Factory(func() *Circle { return &Circle{} }, di.WithFill(), di.WithName("filled")))
+
+	suite.Require().Equal(`active ditest_test.Shape [default] singleton func() ditest_test.Shape
+active ditest_test.Shape [square] factory func() ditest_test.Shape
+active *ditest_test.Square [default] singleton func() *ditest_test.Square if missing *ditest_test.Square [default]
+active *ditest_test.Circle [filled] factory func() *ditest_test.Circle fill
+inactive ditest_test.Shape [default] singleton func() ditest_test.Shape profile(s) [prod]
+pending *ditest_test.Circle [default] implementation if present ditest_test.Circle [default]
+`, ditest.Graph(c))
+}
@@ -0,0 +1,653 @@
+package di
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// goDeclaration is a group of bindings created by a single Singleton(), Factory() or Implementation() call
+type goDeclaration struct {
+	seq          uint64
+	kind         BindingKind
+	constructor  any
+	instance     any
+	caller       string
+	fill         bool
+	optionalArgs bool
+	namedArgs    []NamedKey
+	hooks        bool
+	setters      *setterPolicy
+	retry        retryPolicy
+	timeout      time.Duration
+	outputs      map[int][]string // names of bindings by constructor returned value index
+	abstractions map[int]reflect.Type
+}
+
+// indexes returns sorted indexes of constructor returned values that are still bound
+func (self *goDeclaration) indexes() []int {
+	var out = make([]int, 0, len(self.outputs))
+	for i := range self.outputs {
+		out = append(out, i)
+	}
+
+	sort.Ints(out)
+
+	return out
+}
+
+// overridden checks whether some of constructor returned values were overridden by later declarations
+func (self *goDeclaration) overridden() bool {
+	if self.kind != KindSingleton {
+		return false
+	}
+
+	var t = reflect.TypeOf(self.constructor)
+	var numOut = t.NumOut()
+	if numOut > 0 && isError(t.Out(numOut-1)) {
+		numOut--
+	}
+
+	return len(self.outputs) != numOut
+}
+
+// goExporter renders Go source code reproducing container bindings
+type goExporter struct {
+	pkg      string            // import path of the package code is generated for
+	imports  map[string]string // import aliases by package path
+	bindings map[reflect.Type]map[string]Binding
+	vars     map[uint64][]string // variables singleton instances of declarations are stored in
+	resolver bool                // whether generated code resolves bindings through a resolver
+	seq      int
+}
+
+// ExportGo writes a Go source file for package with provided import path containing NewContainer() function that reproduces
+// active bindings of a container created with NewContainer(). Constructors are referenced by name and called directly where possible,
+// constructors with Optional, variadic or named arguments, setters, retries or timeouts are bound as is. Closures, method values,
+// unexported functions of other packages, instances bound with Implementation() and constructors some of which returned values
+// were overridden by later bindings can't be reproduced, generated code calls a manual function in their place instead. Inactive and pending bindings are listed in a comment.
+func ExportGo(c Container, w io.Writer, pkg string) error {
+	var self, ok = c.(*container)
+	if !ok {
+		return fmt.Errorf("di: %T can't be exported, only containers created with NewContainer() are supported", c)
+	}
+
+	var skipped []string
+	for _, info := range self.Bindings() {
+		if info.State == StateActive {
+			continue
+		}
+
+		skipped = append(skipped, fmt.Sprintf("%s [%s]: %s binding requiring %s declared at %s", info.Abstraction.String(), info.Name, info.State, info.requirements(), path.Base(info.Caller)))
+	}
+
+	self.lock.RLock()
+	var (
+		bindings = copyBindings(self.bindings)
+		entries  = sortedBindings(self.bindings)
+	)
+	self.lock.RUnlock()
+
+	var (
+		decls = make(map[uint64]*goDeclaration)
+		order []*goDeclaration
+	)
+
+	for _, e := range entries {
+		var decl, ok = decls[e.binding.seq]
+		if !ok {
+			decl = &goDeclaration{
+				seq:          e.binding.seq,
+				kind:         e.binding.kind(),
+				constructor:  e.binding.constructor,
+				instance:     e.binding.instance,
+				caller:       e.binding.caller,
+				fill:         e.binding.fill,
+				optionalArgs: e.binding.optionalArgs,
+				namedArgs:    e.binding.namedArgs,
+				hooks:        e.binding.hooks,
+				setters:      e.binding.setters,
+				retry:        e.binding.retry,
+				timeout:      e.binding.timeout,
+				outputs:      make(map[int][]string),
+				abstractions: make(map[int]reflect.Type),
+			}
+
+			if decl.kind == KindFactory {
+				decl.constructor = e.binding.factory
+			}
+
+			decls[e.binding.seq] = decl
+			order = append(order, decl)
+		}
+
+		decl.outputs[e.binding.output] = append(decl.outputs[e.binding.output], e.name)
+		decl.abstractions[e.binding.output] = e.abstraction
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return order[i].seq < order[j].seq })
+
+	var exp = &goExporter{
+		pkg:      pkg,
+		imports:  map[string]string{"github.com/HnH/di": "di"},
+		bindings: bindings,
+		vars:     make(map[uint64][]string),
+	}
+
+	var src, err = exp.render(order, skipped)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(src)
+
+	return err
+}
+
+var anonymousFunc = regexp.MustCompile(`\.func\d+(\.\d+)*$`)
+
+// reference returns an expression referencing a named function or a reason it can't be referenced
+func (self *goExporter) reference(function any) (string, string) {
+	var (
+		name  = funcName(function)
+		slash = strings.LastIndex(name, "/")
+		dot   = strings.Index(name[slash+1:], ".")
+	)
+
+	if dot == -1 {
+		return "", "unnamed function " + name
+	}
+
+	var pkgPath, fn = name[:slash+1+dot], name[slash+1+dot+1:]
+
+	switch {
+	case anonymousFunc.MatchString(name):
+		return "", "anonymous function " + name
+
+	case strings.HasSuffix(fn, "-fm"):
+		return "", "method value " + name
+
+	case strings.Contains(fn, "["):
+		return "", "generic function " + name
+
+	case !token.IsIdentifier(fn):
+		return "", "function " + name
+
+	case pkgPath != self.pkg && !token.IsExported(fn):
+		return "", "unexported function " + name
+	}
+
+	if alias := self.qualifier(pkgPath); alias != "" {
+		return alias + "." + fn, ""
+	}
+
+	return fn, ""
+}
+
+// qualifier returns a package alias used in generated code and registers an import
+func (self *goExporter) qualifier(pkgPath string) string {
+	if pkgPath == self.pkg {
+		return ""
+	}
+
+	if alias, ok := self.imports[pkgPath]; ok {
+		return alias
+	}
+
+	var base = strings.Map(func(r rune) rune {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+
+		return '_'
+	}, path.Base(pkgPath))
+
+	var alias = base
+	for i := 2; self.aliasTaken(alias); i++ {
+		alias = base + strconv.Itoa(i)
+	}
+
+	self.imports[pkgPath] = alias
+
+	return alias
+}
+
+func (self *goExporter) aliasTaken(alias string) bool {
+	for _, a := range self.imports {
+		if a == alias {
+			return true
+		}
+	}
+
+	return false
+}
+
+// typeExpr returns a type expression or false if type can't be expressed in generated code
+func (self *goExporter) typeExpr(t reflect.Type) (string, bool) {
+	if t.Name() != "" {
+		switch {
+		case t.PkgPath() == "":
+			return t.Name(), true
+
+		case strings.Contains(t.Name(), "["), t.PkgPath() != self.pkg && !token.IsExported(t.Name()):
+			return "", false
+		}
+
+		if alias := self.qualifier(t.PkgPath()); alias != "" {
+			return alias + "." + t.Name(), true
+		}
+
+		return t.Name(), true
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		var elem, ok = self.typeExpr(t.Elem())
+		return "*" + elem, ok
+
+	case reflect.Slice:
+		var elem, ok = self.typeExpr(t.Elem())
+		return "[]" + elem, ok
+
+	case reflect.Array:
+		var elem, ok = self.typeExpr(t.Elem())
+		return fmt.Sprintf("[%d]%s", t.Len(), elem), ok
+
+	case reflect.Chan:
+		var elem, ok = self.typeExpr(t.Elem())
+		switch t.ChanDir() {
+		case reflect.RecvDir:
+			return "<-chan " + elem, ok
+
+		case reflect.SendDir:
+			return "chan<- " + elem, ok
+		}
+
+		return "chan " + elem, ok
+
+	case reflect.Map:
+		var key, keyOk = self.typeExpr(t.Key())
+		var elem, elemOk = self.typeExpr(t.Elem())
+		return fmt.Sprintf("map[%s]%s", key, elem), keyOk && elemOk
+
+	case reflect.Func:
+		return self.funcExpr(t)
+
+	case reflect.Interface:
+		return "any", t.NumMethod() == 0
+
+	case reflect.Struct:
+		return "struct{}", t.NumField() == 0
+	}
+
+	return "", false
+}
+
+// funcExpr returns a function type expression
+func (self *goExporter) funcExpr(t reflect.Type) (string, bool) {
+	var (
+		in  = make([]string, t.NumIn())
+		out = make([]string, t.NumOut())
+		ok  = true
+	)
+
+	for i := range in {
+		var expr, exprOk = self.typeExpr(t.In(i))
+		if t.IsVariadic() && i == len(in)-1 {
+			expr = "..." + strings.TrimPrefix(expr, "[]")
+		}
+
+		in[i], ok = expr, ok && exprOk
+	}
+
+	for i := range out {
+		var expr, exprOk = self.typeExpr(t.Out(i))
+		out[i], ok = expr, ok && exprOk
+	}
+
+	var results = strings.Join(out, ", ")
+	if len(out) > 1 {
+		results = "(" + results + ")"
+	}
+
+	return strings.TrimSpace(fmt.Sprintf("func(%s) %s", strings.Join(in, ", "), results)), ok
+}
+
+// render builds a formatted source file
+func (self *goExporter) render(order []*goDeclaration, skipped []string) ([]byte, error) {
+	var (
+		body    bytes.Buffer
+		flagged []string
+	)
+
+	for _, decl := range order {
+		var desc, reason = self.describe(decl), ""
+		if decl.kind == KindImplementation {
+			desc = fmt.Sprintf("%s [%s]", reflect.TypeOf(decl.instance).String(), strings.Join(decl.outputs[0], ","))
+			reason = "instance bound with Implementation()"
+		}
+
+		if reason == "" && decl.overridden() {
+			reason = "some of returned values are overridden by later bindings"
+		}
+
+		var ref string
+		if reason == "" {
+			ref, reason = self.reference(decl.constructor)
+		}
+
+		if reason == "" {
+			if _, ok := self.namedArgs(decl.namedArgs); !ok {
+				reason = "named arguments of types that can't be referenced"
+			}
+		}
+
+		if reason == "" {
+			fmt.Fprintf(&body, "\n// %s declared at %s\n", ref, path.Base(decl.caller))
+
+			if !self.renderDirect(&body, decl, ref) {
+				self.renderReflective(&body, decl, ref)
+			}
+
+			continue
+		}
+
+		var note = fmt.Sprintf("%s: %s declared at %s", desc, reason, path.Base(decl.caller))
+		flagged = append(flagged, note)
+		fmt.Fprintf(&body, "\n// %s\nif err = manual(c, %q); err != nil {\nreturn nil, err\n}\n", note, desc)
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by di.ExportGo. DO NOT EDIT.\n\npackage %s\n\nimport (\n", self.packageName())
+
+	var paths = make([]string, 0, len(self.imports))
+	for p := range self.imports {
+		paths = append(paths, p)
+	}
+
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		if self.imports[p] == path.Base(p) {
+			fmt.Fprintf(&out, "\t%q\n", p)
+		} else {
+			fmt.Fprintf(&out, "\t%s %q\n", self.imports[p], p)
+		}
+	}
+
+	out.WriteString(")\n\n// NewContainer creates a container with bindings exported from a live container. Bindings that can't be reproduced\n")
+	out.WriteString("// are bound by manual, which is called with a container and a binding description in declaration order of the binding.\n")
+	out.WriteString("// manual may be nil if there are no such bindings.\n")
+	out.WriteString("func NewContainer(manual func(c di.Container, binding string) error, opts ...di.Option) (di.Container, error) {\n")
+
+	if len(flagged) > 0 {
+		out.WriteString("// The following bindings can't be reproduced and are bound by manual:\n")
+		for _, f := range flagged {
+			fmt.Fprintf(&out, "//   - %s\n", f)
+		}
+
+		out.WriteString("\n")
+	}
+
+	if len(skipped) > 0 {
+		out.WriteString("// The following inactive and pending bindings are not exported:\n")
+		for _, s := range skipped {
+			fmt.Fprintf(&out, "//   - %s\n", s)
+		}
+
+		out.WriteString("\n")
+	}
+
+	out.WriteString("var c = di.NewContainer(opts...)\n")
+
+	if body.Len() > 0 {
+		out.WriteString("var err error\n")
+	}
+
+	if self.resolver {
+		out.WriteString("var r = di.NewResolver(c)\n")
+	}
+
+	out.Write(body.Bytes())
+	out.WriteString("\nreturn c, nil\n}\n")
+
+	var src, err = format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("di: unable to format exported code: %w", err)
+	}
+
+	return src, nil
+}
+
+// packageName returns a name of the package code is generated for
+func (self *goExporter) packageName() string {
+	var name = path.Base(self.pkg)
+	if !token.IsIdentifier(name) {
+		return "main"
+	}
+
+	return name
+}
+
+// describe lists abstractions and names of a declaration
+func (self *goExporter) describe(decl *goDeclaration) string {
+	var out = make([]string, 0, len(decl.outputs))
+	for _, i := range decl.indexes() {
+		out = append(out, fmt.Sprintf("%s [%s]", decl.abstractions[i].String(), strings.Join(decl.outputs[i], ",")))
+	}
+
+	return strings.Join(out, ", ")
+}
+
+// options renders binding options of a declaration
+func (self *goExporter) options(decl *goDeclaration) string {
+	var (
+		indexes = decl.indexes()
+		names   []string
+	)
+
+	switch {
+	case len(indexes) == 1:
+		names = decl.outputs[indexes[0]]
+
+	default:
+		var same = true
+		for _, i := range indexes {
+			same = same && len(decl.outputs[i]) == 1 && decl.outputs[i][0] == decl.outputs[indexes[0]][0]
+			names = append(names, decl.outputs[i][0])
+		}
+
+		if same {
+			names = names[:1]
+		}
+	}
+
+	var out string
+	if len(names) > 1 || names[0] != DefaultBindName {
+		var quoted = make([]string, len(names))
+		for i, name := range names {
+			quoted[i] = strconv.Quote(name)
+		}
+
+		out += fmt.Sprintf(", di.WithName(%s)", strings.Join(quoted, ", "))
+	}
+
+	if decl.fill {
+		out += ", di.WithFill()"
+	}
+
+	if decl.optionalArgs {
+		out += ", di.WithOptionalArgs()"
+	}
+
+	if decl.hooks {
+		out += ", di.WithHooks()"
+	}
+
+	if args, _ := self.namedArgs(decl.namedArgs); args != "" {
+		out += ", " + args
+	}
+
+	if decl.setters != nil {
+		var quoted = make([]string, len(decl.setters.methods))
+		for i, m := range decl.setters.methods {
+			quoted[i] = strconv.Quote(m)
+		}
+
+		out += fmt.Sprintf(", di.WithSetters(%s)", strings.Join(quoted, ", "))
+	}
+
+	if decl.retry.attempts > 1 {
+		out += fmt.Sprintf(", di.WithRetry(%d, %s.Duration(%d))", decl.retry.attempts, self.qualifier("time"), decl.retry.backoff)
+	}
+
+	if decl.timeout > 0 {
+		out += fmt.Sprintf(", di.WithTimeout(%s.Duration(%d))", self.qualifier("time"), decl.timeout)
+	}
+
+	return out
+}
+
+// namedArgs renders WithNamedArgs() option, returns false if types of the keys can't be expressed in generated code
+func (self *goExporter) namedArgs(keys []NamedKey) (string, bool) {
+	if len(keys) == 0 {
+		return "", true
+	}
+
+	var list = make([]string, len(keys))
+	for i, key := range keys {
+		var expr, ok = self.typeExpr(key.Type())
+		if !ok {
+			return "", false
+		}
+
+		list[i] = fmt.Sprintf("di.Key[%s](%q)", expr, key.Name())
+	}
+
+	return fmt.Sprintf("di.WithNamedArgs(%s)", strings.Join(list, ", ")), true
+}
+
+// renderReflective binds a constructor as is
+func (self *goExporter) renderReflective(w *bytes.Buffer, decl *goDeclaration, ref string) {
+	var method = "Singleton"
+	if decl.kind == KindFactory {
+		method = "Factory"
+	}
+
+	fmt.Fprintf(w, "if err = c.%s(%s%s); err != nil {\nreturn nil, err\n}\n", method, ref, self.options(decl))
+}
+
+// renderDirect calls a constructor directly, returns false if it's not possible
+func (self *goExporter) renderDirect(w *bytes.Buffer, decl *goDeclaration, ref string) bool {
+	var t = reflect.TypeOf(decl.constructor)
+	if t.IsVariadic() || decl.optionalArgs || len(decl.namedArgs) > 0 || decl.setters != nil || decl.retry.attempts > 1 || decl.timeout > 0 {
+		return false
+	}
+
+	var hasError = t.NumOut() > 0 && isError(t.Out(t.NumOut()-1))
+
+	var outs = make([]string, 0, t.NumOut())
+	for i := 0; i < t.NumOut(); i++ {
+		if hasError && i == t.NumOut()-1 {
+			break
+		}
+
+		var expr, ok = self.typeExpr(t.Out(i))
+		if !ok {
+			return false
+		}
+
+		outs = append(outs, expr)
+	}
+
+	var (
+		resolve bytes.Buffer
+		args    = make([]string, t.NumIn())
+		onError = "return nil, err"
+	)
+
+	if decl.kind == KindFactory {
+		onError = "return"
+	}
+
+	for i := range args {
+		if t.In(i).Implements(optionalType) {
+			return false
+		}
+
+		var expr, ok = self.typeExpr(t.In(i))
+		if !ok {
+			return false
+		}
+
+		// collections of all bindings of an element type are only gathered by reflection
+		if _, bound := self.bindings[t.In(i)][DefaultBindName]; !bound && isCollection(t.In(i)) {
+			return false
+		}
+
+		// instances of singletons declared before are passed directly
+		if bnd, bound := self.bindings[t.In(i)][DefaultBindName]; bound && decl.kind == KindSingleton && len(self.vars[bnd.seq]) > bnd.output {
+			args[i] = self.vars[bnd.seq][bnd.output]
+			continue
+		}
+
+		self.seq++
+		self.resolver = true
+		args[i] = fmt.Sprintf("d%d", self.seq)
+		fmt.Fprintf(&resolve, "var %s %s\nif err = r.Resolve(&%s); err != nil {\n%s\n}\n", args[i], expr, args[i], onError)
+	}
+
+	var call = fmt.Sprintf("%s(%s)", ref, strings.Join(args, ", "))
+
+	if decl.kind == KindFactory {
+		fmt.Fprintf(w, "if err = c.Factory(func() (out %s, err error) {\n", outs[0])
+		w.Write(resolve.Bytes())
+
+		if hasError {
+			fmt.Fprintf(w, "return %s\n", call)
+		} else {
+			fmt.Fprintf(w, "return %s, nil\n", call)
+		}
+
+		fmt.Fprintf(w, "}%s); err != nil {\nreturn nil, err\n}\n", self.options(decl))
+
+		return true
+	}
+
+	var vars = make([]string, len(outs))
+	for i := range vars {
+		self.seq++
+		vars[i] = fmt.Sprintf("v%d", self.seq)
+	}
+
+	self.vars[decl.seq] = vars
+	w.Write(resolve.Bytes())
+
+	var lhs = strings.Join(vars, ", ")
+	if hasError {
+		lhs += ", err"
+	}
+
+	fmt.Fprintf(w, "%s := %s\n", lhs, call)
+	if hasError {
+		w.WriteString("if err != nil {\nreturn nil, err\n}\n")
+	}
+
+	var results = strings.Join(outs, ", ")
+	if len(outs) > 1 {
+		results = "(" + results + ")"
+	}
+
+	fmt.Fprintf(w, "if err = c.Singleton(func() %s { return %s }%s); err != nil {\nreturn nil, err\n}\n", results, strings.Join(vars, ", "), self.options(decl))
+
+	return true
+}
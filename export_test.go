@@ -0,0 +1,106 @@
+package di_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/HnH/di"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestExportSuite(t *testing.T) {
+	suite.Run(t, new(ExportSuite))
+}
+
+type ExportSuite struct {
+	suite.Suite
+}
+
+func newOptionalShape(db di.Optional[Database]) Shape {
+	return &Circle{}
+}
+
+func (suite *ExportSuite) TestExportGo() {
+	var c = di.NewContainer()
+	suite.Require().NoError(c.Singleton(context.Background))
+	suite.Require().NoError(c.Singleton(newSlowDatabase))
+	suite.Require().NoError(c.Singleton(newSlowShape, di.WithName("slow"), di.WithFill()))
+	suite.Require().NoError(c.Factory(newMySQL, di.WithName("mysql")))
+	suite.Require().NoError(c.Singleton(newOptionalShape, di.WithName("optional")))
+	suite.Require().NoError(c.Singleton(newRectangle, di.WithName("setters"), di.WithSetters("Set.*", "Use.*")))
+	suite.Require().NoError(c.Singleton(func() Shape { return &Circle{} }, di.WithName("closure")))
+	suite.Require().NoError(c.Implementation(&MySQL{}))
+
+	var buf bytes.Buffer
+	suite.Require().NoError(di.ExportGo(c, &buf, "github.com/HnH/di_test"))
+
+	var src = buf.String()
+	suite.Require().Contains(src, "package di_test\n")
+	suite.Require().Contains(src, "\t\"context\"\n\t\"github.com/HnH/di\"\n")
+	suite.Require().Contains(src, "v1 := context.Background()\n")
+	suite.Require().Contains(src, "v2 := newSlowDatabase()\n")
+	suite.Require().Contains(src, "v3 := newSlowShape(v2)\n")
+	suite.Require().Contains(src, "c.Singleton(func() Shape { return v3 }, di.WithName(\"slow\"), di.WithFill())")
+	suite.Require().Contains(src, "c.Factory(func() (out Database, err error) {\n\t\treturn newMySQL(), nil\n\t}, di.WithName(\"mysql\"))")
+	suite.Require().Contains(src, "c.Singleton(newOptionalShape, di.WithName(\"optional\"))")
+	suite.Require().Contains(src, "c.Singleton(newRectangle, di.WithName(\"setters\"), di.WithSetters(\"Set.*\", \"Use.*\"))")
+	suite.Require().Contains(src, "//   - di_test.Shape [closure]: anonymous function github.com/HnH/di_test.(*ExportSuite).TestExportGo.func1 declared at export_test.go:")
+	suite.Require().Contains(src, "//   - *di_test.MySQL [default]: instance bound with Implementation() declared at export_test.go:")
+	suite.Require().Contains(src, "func NewContainer(manual func(c di.Container, binding string) error, opts ...di.Option) (di.Container, error) {")
+	suite.Require().Contains(src, "if err = manual(c, \"di_test.Shape [closure]\"); err != nil {")
+	suite.Require().Contains(src, "if err = manual(c, \"*di_test.MySQL [default]\"); err != nil {")
+}
+
+func (suite *ExportSuite) TestExportGoSkipped() {
+	var c = di.NewContainer(di.WithProfiles("prod"))
+	suite.Require().NoError(c.Singleton(newCircle, di.WithName("circle")))
+	suite.Require().NoError(c.Singleton(newMySQL, di.WithName("dev"), di.WhenProfile("dev")))
+	suite.Require().NoError(c.Singleton(newMySQL, di.WithName("fallback"), di.IfMissing[Shape]()))
+
+	var buf bytes.Buffer
+	suite.Require().NoError(di.ExportGo(c, &buf, "github.com/HnH/di_test"))
+
+	var src = buf.String()
+	suite.Require().Contains(src, "//   - di_test.Database [dev]: inactive binding requiring profile(s) [dev] declared at export_test.go:")
+	suite.Require().Contains(src, "//   - di_test.Database [fallback]: pending binding requiring if missing di_test.Shape [default] declared at export_test.go:")
+	suite.Require().NotContains(src, "newMySQL")
+}
+
+func (suite *ExportSuite) TestExportGoForeign() {
+	var c = misbound{Container: di.NewContainer()}
+	suite.Require().EqualError(di.ExportGo(c, new(bytes.Buffer), "example.com/app"), "di: di_test.misbound can't be exported, only containers created with NewContainer() are supported")
+}
+
+func (suite *ExportSuite) TestExportGoQualified() {
+	var c = di.NewContainer()
+	suite.Require().NoError(c.Singleton(newCircle, di.WithName("a", "b")))
+	suite.Require().NoError(c.Factory(newSlowShape))
+
+	var buf bytes.Buffer
+	suite.Require().NoError(di.ExportGo(c, &buf, "example.com/app"))
+
+	var src = buf.String()
+	suite.Require().Contains(src, "package app\n")
+	suite.Require().Contains(src, "unexported function github.com/HnH/di_test.newCircle")
+	suite.Require().Contains(src, "unexported function github.com/HnH/di_test.newSlowShape")
+	suite.Require().NotContains(src, "di_test\"")
+}
+
+func newShapeAndDatabase() (Shape, Database) {
+	return &Circle{}, &MySQL{}
+}
+
+func (suite *ExportSuite) TestExportGoOverridden() {
+	var c = di.NewContainer()
+	suite.Require().NoError(c.Singleton(newShapeAndDatabase))
+	suite.Require().NoError(c.Singleton(newRectangle))
+
+	var buf bytes.Buffer
+	suite.Require().NoError(di.ExportGo(c, &buf, "github.com/HnH/di_test"))
+
+	var src = buf.String()
+	suite.Require().Contains(src, "//   - di_test.Database [default]: some of returned values are overridden by later bindings declared at export_test.go:")
+	suite.Require().Contains(src, "if err = manual(c, \"di_test.Database [default]\"); err != nil {")
+	suite.Require().Contains(src, "v1 := newRectangle()\n")
+}
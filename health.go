@@ -0,0 +1,157 @@
+package di
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// HealthChecker is implemented by bound instances that are able to check their own health, e.g. by pinging a server
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// DefaultCheckTimeout is a time a single health check is given by default
+const DefaultCheckTimeout = 5 * time.Second
+
+// HealthCheck runs health checks of singletons and implementations bound to resolver containers
+type HealthCheck struct {
+	resolver Resolver
+	timeout  time.Duration
+}
+
+// HealthReport is a result of running all health checks
+type HealthReport struct {
+	Healthy bool                    `json:"healthy"`
+	Checks  map[string]HealthResult `json:"checks"` // results keyed by a type and a name of a binding, e.g. `*sql.DB [default]`
+}
+
+// HealthResult is a result of a single health check
+type HealthResult struct {
+	Container uint64        `json:"container"`
+	Type      string        `json:"type"`
+	Name      string        `json:"name"`
+	Healthy   bool          `json:"healthy"`
+	Error     string        `json:"error,omitempty"`
+	Duration  time.Duration `json:"duration"`
+}
+
+// Health creates a HealthCheck of resolver containers
+func Health(r Resolver, opts ...Option) *HealthCheck {
+	var options = newHealthOptions(opts)
+
+	return &HealthCheck{resolver: r, timeout: options.timeout}
+}
+
+// Check runs health checks concurrently, each of them is limited by the check timeout. A panicking check is reported
+// as unhealthy with a *PanicError message. Instances bound under several
+// abstractions or names are checked once. Bindings of the same type and name in further containers are keyed with a container ID suffix.
+func (self *HealthCheck) Check(ctx context.Context) HealthReport {
+	var (
+		checks = self.checkers()
+		out    = HealthReport{Healthy: true, Checks: make(map[string]HealthResult, len(checks))}
+		wg     sync.WaitGroup
+		lock   sync.Mutex
+	)
+
+	for key, chk := range checks {
+		wg.Add(1)
+
+		go func(key string, chk healthCheck) {
+			defer wg.Done()
+
+			var (
+				start = time.Now()
+				err   = runHook(ctx, self.timeout, chk.check)
+				res   = HealthResult{Container: chk.container, Type: chk.abstraction.String(), Name: chk.name, Healthy: err == nil, Duration: time.Since(start)}
+			)
+
+			if err != nil {
+				res.Error = err.Error()
+			}
+
+			lock.Lock()
+			defer lock.Unlock()
+
+			out.Checks[key] = res
+			out.Healthy = out.Healthy && res.Healthy
+		}(key, chk)
+	}
+
+	wg.Wait()
+
+	return out
+}
+
+// healthCheck is a bound HealthChecker
+type healthCheck struct {
+	container   uint64
+	abstraction reflect.Type
+	name        string
+	checker     HealthChecker
+}
+
+// check runs a health check converting a panic into a *PanicError, so a single checker can not take down the process
+func (self healthCheck) check(ctx context.Context) (err error) {
+	defer recoverPanic(self.abstraction, self.name, "", &err)
+
+	return self.checker.HealthCheck(ctx)
+}
+
+// checkers lists instances implementing HealthChecker keyed by type and name: implementations provided with Resolver.With(),
+// then singletons and implementations bound to containers. Bound instances are read as is, so checks are not counted as resolutions.
+func (self *HealthCheck) checkers() map[string]healthCheck {
+	var (
+		out  = make(map[string]healthCheck)
+		seen = make(map[any]bool)
+		add  = func(container uint64, abstraction reflect.Type, name string, instance any) {
+			var checker, ok = instance.(HealthChecker)
+			if !ok {
+				return
+			}
+
+			// the same instance may be bound under several abstractions or names
+			if key, ok := instanceKey(checker); ok {
+				if seen[key] {
+					return
+				}
+
+				seen[key] = true
+			}
+
+			var key = fmt.Sprintf("%s [%s]", abstraction.String(), name)
+			if _, taken := out[key]; taken {
+				key = fmt.Sprintf("%s #%d", key, container)
+			}
+
+			out[key] = healthCheck{container: container, abstraction: abstraction, name: name, checker: checker}
+		}
+	)
+
+	if rsl, ok := self.resolver.(*resolver); ok {
+		for _, inst := range rsl.implementations {
+			add(0, reflect.TypeOf(inst), DefaultBindName, inst)
+		}
+	}
+
+	for _, c := range self.resolver.Containers() {
+		for _, info := range c.Bindings() {
+			if info.State != StateActive || info.Kind == KindFactory {
+				continue
+			}
+
+			var list, err = c.ListBindings(info.Abstraction)
+			if err != nil {
+				continue
+			}
+
+			if bnd, ok := list[info.Name]; ok && bnd.instance != nil {
+				add(info.Container, info.Abstraction, info.Name, bnd.instance)
+			}
+		}
+	}
+
+	return out
+}
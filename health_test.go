@@ -0,0 +1,173 @@
+package di_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/HnH/di"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestHealthSuite(t *testing.T) {
+	suite.Run(t, new(HealthSuite))
+}
+
+type HealthSuite struct {
+	suite.Suite
+}
+
+// pinger is a HealthChecker
+type pinger struct {
+	err   error
+	delay time.Duration
+}
+
+func (p *pinger) HealthCheck(ctx context.Context) error {
+	select {
+	case <-time.After(p.delay):
+		return p.err
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (suite *HealthSuite) TestCheck() {
+	var (
+		c1 = di.NewContainer()
+		c2 = di.NewContainer()
+		db = &pinger{}
+	)
+
+	suite.Require().NoError(c1.Implementation(db))
+	suite.Require().NoError(c1.Singleton(func() HealthyDatabase { return db }, di.WithName("db")))
+	suite.Require().NoError(c1.Singleton(func() *pinger { return &pinger{err: errors.New("connection refused")} }, di.WithName("cache")))
+	suite.Require().NoError(c1.Factory(func() *pinger { return &pinger{err: errors.New("never checked")} }, di.WithName("factory")))
+	suite.Require().NoError(c1.Singleton(newCircle))
+	suite.Require().NoError(c2.Implementation(&pinger{}))
+
+	var report = di.Health(di.NewResolver(c1, c2)).Check(context.Background())
+	suite.Require().False(report.Healthy)
+	suite.Require().Len(report.Checks, 3)
+
+	var res = report.Checks["*di_test.pinger [default]"]
+	suite.Require().True(res.Healthy)
+	suite.Require().Equal(c1.ID(), res.Container)
+	suite.Require().Equal("*di_test.pinger", res.Type)
+	suite.Require().Equal("default", res.Name)
+
+	res = report.Checks["*di_test.pinger [cache]"]
+	suite.Require().False(res.Healthy)
+	suite.Require().Equal("connection refused", res.Error)
+
+	res = report.Checks[fmt.Sprintf("*di_test.pinger [default] #%d", c2.ID())]
+	suite.Require().True(res.Healthy)
+	suite.Require().Equal(c2.ID(), res.Container)
+}
+
+func (suite *HealthSuite) TestTimeout() {
+	var c = di.NewContainer()
+	suite.Require().NoError(c.Implementation(&pinger{delay: time.Second}))
+
+	var report = di.Health(di.NewResolver(c), di.WithCheckTimeout(10*time.Millisecond)).Check(context.Background())
+	suite.Require().False(report.Healthy)
+	suite.Require().Equal(context.DeadlineExceeded.Error(), report.Checks["*di_test.pinger [default]"].Error)
+
+	report = di.Health(di.NewResolver(di.NewContainer())).Check(context.Background())
+	suite.Require().True(report.Healthy)
+	suite.Require().Empty(report.Checks)
+}
+
+// panicker is a HealthChecker that panics
+type panicker struct{}
+
+func (panicker) HealthCheck(ctx context.Context) error {
+	panic("nil connection")
+}
+
+func (suite *HealthSuite) TestPanic() {
+	var c = di.NewContainer()
+	suite.Require().NoError(c.Implementation(panicker{}))
+	suite.Require().NoError(c.Implementation(&pinger{}))
+
+	for _, timeout := range []time.Duration{0, time.Second} {
+		var report = di.Health(di.NewResolver(c), di.WithCheckTimeout(timeout)).Check(context.Background())
+		suite.Require().False(report.Healthy)
+		suite.Require().Len(report.Checks, 2)
+		suite.Require().True(report.Checks["*di_test.pinger [default]"].Healthy)
+
+		var res = report.Checks["di_test.panicker [default]"]
+		suite.Require().False(res.Healthy)
+		suite.Require().Equal("di: panic in di_test.panicker: nil connection", res.Error)
+	}
+}
+
+// status is a comparable HealthChecker that may hold unhashable details
+type status struct {
+	details any
+}
+
+func (s status) HealthCheck(ctx context.Context) error {
+	return nil
+}
+
+// resolveCounter counts resolutions
+type resolveCounter struct {
+	di.NopObserver
+	resolved int
+}
+
+func (r *resolveCounter) OnResolveStart(e di.Event) {
+	r.resolved++
+}
+
+func (suite *HealthSuite) TestNotResolved() {
+	var (
+		rec = new(resolveCounter)
+		c   = di.NewContainer(di.WithObserver(rec))
+	)
+
+	suite.Require().NoError(c.Implementation(&pinger{}))
+	suite.Require().NoError(c.Singleton(func() HealthyDatabase { return &pinger{} }))
+
+	var h = di.Health(di.NewResolver(c).Observe(rec))
+	suite.Require().Len(h.Check(context.Background()).Checks, 2)
+	suite.Require().Len(h.Check(context.Background()).Checks, 2)
+	suite.Require().Zero(rec.resolved)
+
+	for _, info := range c.Bindings() {
+		suite.Require().Zero(info.Resolutions)
+	}
+}
+
+func (suite *HealthSuite) TestWith() {
+	var c = di.NewContainer()
+	suite.Require().NoError(c.Implementation(&pinger{}))
+
+	var report = di.Health(di.NewResolver(c).With(&pinger{err: errors.New("request scoped")})).Check(context.Background())
+	suite.Require().False(report.Healthy)
+	suite.Require().Len(report.Checks, 2)
+	suite.Require().Equal("request scoped", report.Checks["*di_test.pinger [default]"].Error)
+	suite.Require().Zero(report.Checks["*di_test.pinger [default]"].Container)
+	suite.Require().True(report.Checks[fmt.Sprintf("*di_test.pinger [default] #%d", c.ID())].Healthy)
+}
+
+func (suite *HealthSuite) TestUnhashable() {
+	var c = di.NewContainer()
+	suite.Require().NoError(c.Implementation(status{details: []string{"replica"}}))
+	suite.Require().NoError(c.Implementation(status{details: "primary"}, di.WithName("primary")))
+	suite.Require().NoError(c.Singleton(func() HealthyDatabase { return status{details: "primary"} }))
+
+	var report = di.Health(di.NewResolver(c)).Check(context.Background())
+	suite.Require().True(report.Healthy)
+	suite.Require().Len(report.Checks, 2) // equal values are checked once
+	suite.Require().Contains(report.Checks, "di_test.status [default]")
+	suite.Require().Contains(report.Checks, "di_test.status [primary]")
+}
+
+type HealthyDatabase interface {
+	HealthCheck(ctx context.Context) error
+}
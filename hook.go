@@ -0,0 +1,54 @@
+package di
+
+import (
+	"context"
+	"reflect"
+)
+
+// Initializer implements an `Init()` method which is called after Construct() in the same way.
+// Methods with other signatures are called only if a binding is declared with WithHooks().
+type Initializer interface {
+	Init(context.Context) error
+}
+
+// postConstructHooks lists methods called on instances after they are created, filled and their setters are called, in the order of calling.
+// Methods implementing the interface are always called, other signatures are called only if the binding opts into them.
+var postConstructHooks = []struct {
+	name  string
+	iface reflect.Type
+}{
+	{name: "Construct", iface: reflect.TypeOf((*Constructor)(nil)).Elem()},
+	{name: "Init", iface: reflect.TypeOf((*Initializer)(nil)).Elem()},
+}
+
+// postConstructHook is a hook method of an instance
+type postConstructHook struct {
+	name   string // name the hook is profiled with
+	method any
+}
+
+// hooks lists post-construct hook methods of an instance. If custom is set, methods that return nothing or a single error
+// are treated as hooks regardless of their arguments.
+func hooks(instance any, custom bool) []postConstructHook {
+	var v = reflect.ValueOf(instance)
+	if !v.IsValid() {
+		return nil
+	}
+
+	var out []postConstructHook
+	for _, h := range postConstructHooks {
+		var method = v.MethodByName(h.name)
+		if !method.IsValid() || !v.Type().Implements(h.iface) && !(custom && isHook(method.Type())) {
+			continue
+		}
+
+		out = append(out, postConstructHook{name: v.Type().String() + "." + h.name, method: method.Interface()})
+	}
+
+	return out
+}
+
+// isHook checks whether a method returns nothing or a single error
+func isHook(method reflect.Type) bool {
+	return method.NumOut() == 0 || method.NumOut() == 1 && isError(method.Out(0))
+}
@@ -0,0 +1,167 @@
+package di_test
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"reflect"
+	"testing"
+
+	"github.com/HnH/di"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestHookSuite(t *testing.T) {
+	suite.Run(t, new(HookSuite))
+}
+
+type HookSuite struct {
+	suite.Suite
+}
+
+// hooked records calls of its post-construct hooks
+type hooked struct {
+	Mailer Shape `di:"type"`
+
+	shape Shape
+	db    Database
+	calls []string
+	err   error
+}
+
+func (h *hooked) Construct(s Shape) {
+	h.shape = s
+	h.calls = append(h.calls, "Construct")
+}
+
+func (h *hooked) Init(db Database) error {
+	h.db = db
+	h.calls = append(h.calls, "Init")
+
+	return h.err
+}
+
+// builder has an Init() method that is not a hook
+type builder struct {
+	initialized bool
+}
+
+func (b *builder) Init() *builder {
+	b.initialized = true
+	return b
+}
+
+// initializer implements Initializer interface
+type initializer struct {
+	ctx context.Context
+}
+
+func (i *initializer) Init(ctx context.Context) error {
+	i.ctx = ctx
+	return nil
+}
+
+// hookKey is a context key the initializer test uses
+type hookKey struct{}
+
+func (suite *HookSuite) container() di.Container {
+	var c = di.NewContainer()
+	suite.Require().NoError(c.Singleton(newCircle))
+	suite.Require().NoError(c.Singleton(newMySQL))
+
+	return c
+}
+
+func (suite *HookSuite) TestSingleton() {
+	var (
+		c = suite.container()
+		h = new(hooked)
+	)
+
+	suite.Require().NoError(c.Singleton(func() *hooked { return h }, di.WithHooks()))
+	suite.Require().Equal([]string{"Construct", "Init"}, h.calls)
+	suite.Require().IsType(&Circle{}, h.shape)
+	suite.Require().IsType(&MySQL{}, h.db)
+	suite.Require().Nil(h.Mailer)
+}
+
+func (suite *HookSuite) TestFactory() {
+	var c = suite.container()
+	suite.Require().NoError(c.Factory(func() *hooked { return new(hooked) }, di.WithHooks()))
+
+	var h *hooked
+	suite.Require().NoError(di.NewResolver(c).Resolve(&h))
+	suite.Require().Equal([]string{"Construct", "Init"}, h.calls)
+}
+
+func (suite *HookSuite) TestImplementation() {
+	var (
+		c = suite.container()
+		h = new(hooked)
+	)
+
+	suite.Require().NoError(c.Implementation(h, di.WithFill(), di.WithHooks()))
+	suite.Require().Equal([]string{"Construct", "Init"}, h.calls)
+	suite.Require().IsType(&Circle{}, h.Mailer)
+
+	var r *hooked
+	suite.Require().NoError(di.NewResolver(c).Resolve(&r))
+	suite.Require().Same(h, r)
+}
+
+func (suite *HookSuite) TestImplementationFailure() {
+	var c = suite.container()
+	suite.Require().EqualError(c.Implementation(&initializer{}), "di: no binding found for context.Context")
+
+	var errInit = errors.New("init failed")
+	suite.Require().ErrorIs(c.Implementation(&hooked{err: errInit}, di.WithHooks()), errInit)
+
+	var _, err = c.ListBindings(reflect.TypeOf(&hooked{}))
+	suite.Require().EqualError(err, "di: no binding found for *di_test.hooked")
+}
+
+func (suite *HookSuite) TestInitializer() {
+	var (
+		c   = suite.container()
+		ctx = context.WithValue(context.Background(), hookKey{}, "value")
+		i   = new(initializer)
+	)
+
+	suite.Require().NoError(c.Singleton(func() context.Context { return ctx }))
+	suite.Require().NoError(c.Implementation(i))
+	suite.Require().Implements((*di.Initializer)(nil), i)
+	suite.Require().Equal(ctx, i.ctx)
+}
+
+func (suite *HookSuite) TestCustomNotRequested() {
+	var (
+		c = di.NewContainer()
+		h = new(hooked)
+	)
+
+	// arguments of the hooks aren't bound, but the hooks are not called without WithHooks()
+	suite.Require().NoError(c.Singleton(func() *hooked { return h }))
+	suite.Require().NoError(c.Implementation(&hooked{err: errors.New("init failed")}))
+	suite.Require().Empty(h.calls)
+}
+
+func (suite *HookSuite) TestUnrelatedInit() {
+	var c = di.NewContainer()
+
+	// (*flag.FlagSet).Init(string, flag.ErrorHandling) is not a post-construct hook
+	suite.Require().NoError(c.Implementation(flag.NewFlagSet("implementation", flag.ContinueOnError)))
+	suite.Require().NoError(c.Singleton(func() *flag.FlagSet { return flag.NewFlagSet("singleton", flag.ContinueOnError) }, di.WithName("singleton")))
+
+	var fs *flag.FlagSet
+	suite.Require().NoError(di.NewResolver(c).Resolve(&fs, di.WithName("singleton")))
+	suite.Require().Equal("singleton", fs.Name())
+}
+
+func (suite *HookSuite) TestNotHook() {
+	var c = suite.container()
+	suite.Require().NoError(c.Singleton(func() *builder { return new(builder) }))
+
+	var b *builder
+	suite.Require().NoError(di.NewResolver(c).Resolve(&b))
+	suite.Require().False(b.initialized)
+}
@@ -0,0 +1,78 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// nameTagPrefix is a prefix of `di:"name=http.port"` struct tags that fill fields with bindings of explicitly provided names
+const nameTagPrefix = "name="
+
+// NamedKey identifies a binding by its type and name, it is implemented by Key[T]
+type NamedKey interface {
+	Type() reflect.Type
+	Name() string
+}
+
+// Key is a typed token of a binding of T available under a name, e.g. `di.Key[int]("http.port")`.
+// Keys bind and resolve values and pass them to functions with WithNamedArgs().
+type Key[T any] string
+
+// Name returns a name of the binding
+func (self Key[T]) Name() string {
+	return string(self)
+}
+
+// Type returns a type of the binding
+func (self Key[T]) Type() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// String implements fmt.Stringer interface
+func (self Key[T]) String() string {
+	return fmt.Sprintf("%s [%s]", self.Type().String(), self.Name())
+}
+
+// Bind binds a value as T under the name of the key, see Container.Value()
+func (self Key[T]) Bind(c Container, value T, opts ...Option) error {
+	return c.Value(self.Name(), value, append(opts, bindAs(self.Type(), callerLocation(2)))...)
+}
+
+// Resolve resolves a value bound under the name of the key
+func (self Key[T]) Resolve(r Resolver) (out T, err error) {
+	err = r.Resolve(&out, WithName(self.Name()))
+	return
+}
+
+// argumentNames returns binding names function arguments are resolved with. Keys are matched with arguments of the same type
+// in order, Optional[T] arguments are matched with keys of T. Other arguments are resolved with DefaultBindName.
+func argumentNames(function reflect.Type, keys []NamedKey) ([]string, error) {
+	var (
+		out  = make([]string, function.NumIn())
+		used = make([]bool, len(keys))
+	)
+
+	for i := range out {
+		out[i] = DefaultBindName
+
+		var t = function.In(i)
+		if isOptional(t) {
+			t = reflect.Zero(t).Interface().(optional).abstraction()
+		}
+
+		for j, key := range keys {
+			if !used[j] && key.Type() == t {
+				out[i], used[j] = key.Name(), true
+				break
+			}
+		}
+	}
+
+	for j, key := range keys {
+		if !used[j] {
+			return nil, fmt.Errorf("di: named argument %s [%s] doesn't match any argument of %s", key.Type().String(), key.Name(), function.String())
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,158 @@
+package di_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/HnH/di"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestKeySuite(t *testing.T) {
+	suite.Run(t, new(KeySuite))
+}
+
+type KeySuite struct {
+	suite.Suite
+}
+
+const (
+	httpPort    = di.Key[int]("http.port")
+	httpHost    = di.Key[string]("http.host")
+	httpTimeout = di.Key[time.Duration]("http.timeout")
+	adminPort   = di.Key[int]("admin.port")
+)
+
+// endpoint is configured with scalar values
+type endpoint struct {
+	host    string
+	port    int
+	admin   int
+	timeout time.Duration
+}
+
+func newEndpoint(host string, port, admin int, timeout time.Duration) *endpoint {
+	return &endpoint{host: host, port: port, admin: admin, timeout: timeout}
+}
+
+func (suite *KeySuite) container() di.Container {
+	var c = di.NewContainer()
+	suite.Require().NoError(c.Value("http.port", 8080))
+	suite.Require().NoError(httpHost.Bind(c, "localhost"))
+	suite.Require().NoError(httpTimeout.Bind(c, 5*time.Second))
+	suite.Require().NoError(adminPort.Bind(c, 9090))
+
+	return c
+}
+
+func (suite *KeySuite) TestValue() {
+	var (
+		c = suite.container()
+		r = di.NewResolver(c)
+	)
+
+	var port int
+	suite.Require().NoError(r.Resolve(&port, di.WithName("http.port")))
+	suite.Require().Equal(8080, port)
+
+	var timeout, err = httpTimeout.Resolve(r)
+	suite.Require().NoError(err)
+	suite.Require().Equal(5*time.Second, timeout)
+
+	var info = c.Bindings()[0]
+	suite.Require().Equal("http.port", info.Name)
+	suite.Require().Equal(di.KindImplementation, info.Kind)
+	suite.Require().Contains(info.Caller, "key_test.go")
+	suite.Require().Contains(c.Bindings()[1].Caller, "key_test.go")
+
+	_, err = di.Key[int]("grpc.port").Resolve(r)
+	suite.Require().EqualError(err, "di: no binding found for int")
+
+	suite.Require().EqualError(c.Value("nil", nil), "di: the value must not be nil")
+	suite.Require().Equal("time.Duration [http.timeout]", httpTimeout.String())
+}
+
+func (suite *KeySuite) TestInterface() {
+	var c = di.NewContainer()
+	suite.Require().NoError(di.Key[Shape]("main").Bind(c, newCircle()))
+
+	var s Shape
+	suite.Require().NoError(di.NewResolver(c).Resolve(&s, di.WithName("main")))
+	suite.Require().IsType(&Circle{}, s)
+}
+
+func (suite *KeySuite) TestNamedArgs() {
+	var c = suite.container()
+	suite.Require().NoError(c.Singleton(newEndpoint, di.WithNamedArgs(httpHost, httpPort, adminPort, httpTimeout)))
+
+	var e *endpoint
+	suite.Require().NoError(di.NewResolver(c).Resolve(&e))
+	suite.Require().Equal(&endpoint{host: "localhost", port: 8080, admin: 9090, timeout: 5 * time.Second}, e)
+
+	// keys are matched with arguments of the same type in order
+	suite.Require().NoError(c.Factory(newEndpoint, di.WithName("swapped"), di.WithNamedArgs(adminPort, httpHost, httpPort, httpTimeout)))
+	suite.Require().NoError(di.NewResolver(c).Resolve(&e, di.WithName("swapped")))
+	suite.Require().Equal(9090, e.port)
+	suite.Require().Equal(8080, e.admin)
+}
+
+func (suite *KeySuite) TestCall() {
+	var r = di.NewResolver(suite.container())
+
+	suite.Require().NoError(r.Call(func(port int, timeout di.Optional[time.Duration], missing di.Optional[string]) {
+		suite.Require().Equal(8080, port)
+		suite.Require().Equal(di.Optional[time.Duration]{Value: 5 * time.Second, Present: true}, timeout)
+		suite.Require().False(missing.Present)
+	}, di.WithNamedArgs(httpPort, httpTimeout, di.Key[string]("http.path"))))
+
+	suite.Require().EqualError(
+		r.Call(func(port int) {}, di.WithNamedArgs(di.Key[int]("grpc.port"))),
+		"di: no binding found for int [grpc.port]",
+	)
+
+	suite.Require().EqualError(
+		r.Call(func(port int) {}, di.WithNamedArgs(httpPort, httpHost)),
+		"di: named argument string [http.host] doesn't match any argument of func(int)",
+	)
+}
+
+func (suite *KeySuite) TestValidate() {
+	var c = suite.container()
+	suite.Require().NoError(c.Factory(newEndpoint, di.WithNamedArgs(httpHost, httpPort, di.Key[int]("grpc.port"), httpTimeout)))
+
+	var err = c.Validate()
+	suite.Require().Error(err)
+	suite.Require().Contains(err.Error(), "*di_test.endpoint [default] declared at [")
+	suite.Require().Contains(err.Error(), "]: di: no binding found for int [grpc.port]")
+}
+
+func (suite *KeySuite) TestFill() {
+	var target struct {
+		Port    int           `di:"name=http.port"`
+		Timeout time.Duration `di:"name=http.timeout"`
+		Path    string        `di:"name=http.path,omitempty"`
+	}
+
+	var r = di.NewResolver(suite.container())
+	suite.Require().NoError(r.Fill(&target))
+	suite.Require().Equal(8080, target.Port)
+	suite.Require().Equal(5*time.Second, target.Timeout)
+	suite.Require().Empty(target.Path)
+
+	var invalid struct {
+		Port int `di:"name="`
+	}
+
+	suite.Require().EqualError(r.Fill(&invalid), "di: Port has an invalid struct tag: filling *struct { Port int \"di:\\\"name=\\\"\" }")
+}
+
+func (suite *KeySuite) TestExportGo() {
+	var c = suite.container()
+	suite.Require().NoError(c.Singleton(newEndpoint, di.WithNamedArgs(httpHost, httpPort, adminPort, httpTimeout)))
+
+	var buf bytes.Buffer
+	suite.Require().NoError(di.ExportGo(c, &buf, "github.com/HnH/di_test"))
+	suite.Require().Contains(buf.String(), `c.Singleton(newEndpoint, di.WithNamedArgs(di.Key[string]("http.host"), di.Key[int]("http.port"), di.Key[int]("admin.port"), di.Key[time.Duration]("http.timeout")))`)
+	suite.Require().Contains(buf.String(), "int [http.port]: instance bound with Implementation() declared at key_test.go:")
+}
@@ -0,0 +1,62 @@
+package di
+
+import (
+	"reflect"
+	"time"
+)
+
+// Event describes a single step performed by a container or a resolver
+type Event struct {
+	Container   uint64        // ID of a container binding belongs to, 0 for implementations passed to Resolver.With()
+	Abstraction reflect.Type  // bound, resolved or filled type, a function type for binding and Call() errors
+	Name        string        // binding name
+	Kind        BindingKind   // binding kind, empty for Fill() and Call() events
+	Caller      string        // location binding was declared at
+	Duration    time.Duration // time spent, set for OnResolveEnd, OnConstruct and OnFill
+	Setters     []string      // setter methods called on an instance, set for OnBind, OnConstruct and OnFill
+	Err         error         // error that occurred, set for OnError and failed OnResolveEnd
+}
+
+// Observer is notified about what containers and resolvers do. Observers registered on a container with WithObserver()
+// see binding events of the container and resolution events of every resolver working against it,
+// observers registered with Resolver.Observe() see resolution events of the resolver only.
+// Observers are called synchronously and must not block.
+type Observer interface {
+	OnBind(Event)         // binding was registered
+	OnResolveStart(Event) // binding resolution started
+	OnResolveEnd(Event)   // binding resolution finished
+	OnConstruct(Event)    // singleton constructor or factory method was called, once per call regardless of the number of names instances are bound under
+	OnFill(Event)         // receiver was filled
+	OnError(Event)        // binding, resolution, filling or a function call failed
+}
+
+// NopObserver implements Observer interface with no-op methods, it can be embedded to implement only some of them
+type NopObserver struct{}
+
+// OnBind implements Observer interface
+func (NopObserver) OnBind(Event) {}
+
+// OnResolveStart implements Observer interface
+func (NopObserver) OnResolveStart(Event) {}
+
+// OnResolveEnd implements Observer interface
+func (NopObserver) OnResolveEnd(Event) {}
+
+// OnConstruct implements Observer interface
+func (NopObserver) OnConstruct(Event) {}
+
+// OnFill implements Observer interface
+func (NopObserver) OnFill(Event) {}
+
+// OnError implements Observer interface
+func (NopObserver) OnError(Event) {}
+
+// observers is a list of observers events are dispatched to
+type observers []Observer
+
+// notify calls fn for each of observers
+func (self observers) notify(fn func(Observer)) {
+	for _, o := range self {
+		fn(o)
+	}
+}
@@ -0,0 +1,172 @@
+package di_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/HnH/di"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestObserverSuite(t *testing.T) {
+	suite.Run(t, new(ObserverSuite))
+}
+
+type ObserverSuite struct {
+	suite.Suite
+}
+
+// eventRecorder records observed events in a short form
+type eventRecorder struct {
+	events []string
+	last   di.Event
+}
+
+func (r *eventRecorder) record(kind string, e di.Event) {
+	var t = "<nil>"
+	if e.Abstraction != nil {
+		t = e.Abstraction.String()
+	}
+
+	r.events = append(r.events, fmt.Sprintf("%s %s [%s] %s", kind, t, e.Name, e.Kind))
+	r.last = e
+}
+
+func (r *eventRecorder) OnBind(e di.Event)         { r.record("bind", e) }
+func (r *eventRecorder) OnResolveStart(e di.Event) { r.record("start", e) }
+func (r *eventRecorder) OnResolveEnd(e di.Event)   { r.record("end", e) }
+func (r *eventRecorder) OnConstruct(e di.Event)    { r.record("construct", e) }
+func (r *eventRecorder) OnFill(e di.Event)         { r.record("fill", e) }
+func (r *eventRecorder) OnError(e di.Event)        { r.record("error", e) }
+
+func (suite *ObserverSuite) TestContainerObserver() {
+	var (
+		rec = new(eventRecorder)
+		c   = di.NewContainer(di.WithObserver(rec))
+		r   = di.NewResolver(c)
+	)
+
+	suite.Require().NoError(c.Singleton(newCircle, di.WithName("a", "b")))
+	suite.Require().NoError(c.Factory(newMySQL))
+	suite.Require().NoError(c.Implementation(&MySQL{}))
+	suite.Require().NoError(c.Singleton(newRectangle, di.WhenProfile("prod")))
+	suite.Require().Equal([]string{
+		"construct di_test.Shape [a] singleton",
+		"bind di_test.Shape [a] singleton",
+		"bind di_test.Shape [b] singleton",
+		"bind di_test.Database [default] factory",
+		"bind *di_test.MySQL [default] implementation",
+	}, rec.events)
+	suite.Require().Equal(c.ID(), rec.last.Container)
+	suite.Require().Contains(rec.last.Caller, "observer_test.go")
+
+	rec.events = nil
+	suite.Require().NoError(r.Call(func(Database) {}))
+	suite.Require().Equal([]string{
+		"start di_test.Database [default] factory",
+		"construct di_test.Database [default] factory",
+		"end di_test.Database [default] factory",
+	}, rec.events)
+	suite.Require().NoError(rec.last.Err)
+
+	rec.events = nil
+	var target = struct {
+		Shape Shape `di:"name"`
+	}{}
+
+	suite.Require().Error(r.Fill(&target))
+	suite.Require().NoError(r.Fill(&[]Shape{}))
+	suite.Require().Len(rec.events, 6)
+	suite.Require().Equal(`error *struct { Shape di_test.Shape "di:\"name\"" } [] `, rec.events[0])
+	suite.Require().Equal("fill *[]di_test.Shape [] ", rec.events[5])
+
+	// constructor returning several instances is reported once
+	rec.events = nil
+	suite.Require().NoError(di.NewContainer(di.WithObserver(rec)).Singleton(func() (Shape, Database) { return &Circle{}, &MySQL{} }, di.WithName("multi")))
+	suite.Require().Equal([]string{
+		"construct di_test.Shape [multi] singleton",
+		"bind di_test.Shape [multi] singleton",
+		"bind di_test.Database [multi] singleton",
+	}, rec.events)
+}
+
+func (suite *ObserverSuite) TestErrors() {
+	var (
+		rec = new(eventRecorder)
+		c   = di.NewContainer(di.WithObserver(rec))
+		r   = di.NewResolver(c)
+		err = errors.New("dummy error")
+	)
+
+	suite.Require().ErrorIs(c.Singleton(func() (Shape, error) { return nil, err }), err)
+	suite.Require().Equal([]string{"error func() (di_test.Shape, error) [] singleton"}, rec.events)
+	suite.Require().Equal(err, rec.last.Err)
+
+	rec.events = nil
+	suite.Require().NoError(c.Factory(func() (Database, error) { return nil, err }))
+	suite.Require().ErrorIs(r.Call(func(Database) {}), err)
+	suite.Require().Equal([]string{
+		"bind di_test.Database [default] factory",
+		"start di_test.Database [default] factory",
+		"end di_test.Database [default] factory",
+		"error di_test.Database [default] factory",
+		"error func(di_test.Database) [] ",
+	}, rec.events)
+
+	rec.events = nil
+	suite.Require().Error(r.Call(func(Shape) {}))
+	suite.Require().Equal([]string{"error func(di_test.Shape) [] "}, rec.events)
+
+	rec.events = nil
+	var shape Shape
+	suite.Require().EqualError(r.Resolve(&shape, di.WithName("missing")), "di: no binding found for di_test.Shape")
+	suite.Require().Equal([]string{"error di_test.Shape [missing] "}, rec.events)
+	suite.Require().EqualError(rec.last.Err, "di: no binding found for di_test.Shape")
+
+	rec.events = nil
+	var db Database
+	suite.Require().ErrorIs(r.Resolve(&db), err)
+	suite.Require().Equal([]string{
+		"start di_test.Database [default] factory",
+		"end di_test.Database [default] factory",
+		"error di_test.Database [default] factory",
+	}, rec.events)
+}
+
+func (suite *ObserverSuite) TestResolverObserver() {
+	var (
+		rec      = new(eventRecorder)
+		c        = di.NewContainer()
+		r        = di.NewResolver(c)
+		observed = r.Observe(rec)
+	)
+
+	suite.Require().NoError(c.Factory(newMySQL))
+	suite.Require().Empty(rec.events)
+
+	var db Database
+	suite.Require().NoError(r.Resolve(&db))
+	suite.Require().Empty(rec.events)
+
+	suite.Require().NoError(observed.Resolve(&db))
+	suite.Require().NoError(observed.With(&Circle{}).Call(func(*Circle) {}))
+	suite.Require().Equal([]string{
+		"start di_test.Database [default] factory",
+		"construct di_test.Database [default] factory",
+		"end di_test.Database [default] factory",
+		"start *di_test.Circle [default] implementation",
+		"end *di_test.Circle [default] implementation",
+	}, rec.events)
+	suite.Require().Equal(uint64(0), rec.last.Container)
+	suite.Require().Equal(reflect.TypeOf(&Circle{}), rec.last.Abstraction)
+}
+
+func (suite *ObserverSuite) TestNopObserver() {
+	var obs = struct{ di.NopObserver }{}
+	var c = di.NewContainer(di.WithObserver(obs))
+
+	suite.Require().NoError(c.Factory(newMySQL))
+	suite.Require().NoError(di.NewResolver(c).Call(func(Database) {}))
+}
@@ -0,0 +1,38 @@
+package di
+
+import "reflect"
+
+// Optional wraps a function argument that is injected only if a binding for T is available.
+// If no binding was found Present is false and Value holds a zero value of T.
+type Optional[T any] struct {
+	Value   T
+	Present bool
+}
+
+// abstraction returns the wrapped type that has to be resolved
+func (Optional[T]) abstraction() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// set stores resolved instance and marks it as present
+func (self *Optional[T]) set(instance any) {
+	self.Value, _ = instance.(T)
+	self.Present = true
+}
+
+// optional is implemented by every Optional[T] type
+type optional interface {
+	abstraction() reflect.Type
+}
+
+// optionalSetter is implemented by every *Optional[T] type
+type optionalSetter interface {
+	set(any)
+}
+
+var optionalType = reflect.TypeOf((*optional)(nil)).Elem()
+
+// isOptional checks whether provided type is an Optional[T] wrapper
+func isOptional(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.Implements(optionalType)
+}
@@ -1,5 +1,14 @@
 package di
 
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"syscall"
+	"time"
+)
+
 // Option represents single option type
 type Option func(Options)
 
@@ -23,6 +32,96 @@ type FillingOption interface {
 	SetFill(bool)
 }
 
+// OptionalArgsOption supports passing zero values for unresolvable arguments
+type OptionalArgsOption interface {
+	SetOptionalArgs(bool)
+}
+
+// ProfileOption supports setting active profiles
+type ProfileOption interface {
+	SetProfiles(...string)
+}
+
+// ProfileConditionOption supports setting profiles a binding is active for
+type ProfileConditionOption interface {
+	SetProfileCondition(...string)
+}
+
+// ReinstantiateOption supports setting a reinstantiate flag
+type ReinstantiateOption interface {
+	SetReinstantiate(bool)
+}
+
+// ObserverOption supports registering observers
+type ObserverOption interface {
+	AddObserver(...Observer)
+}
+
+// ProfilerOption supports setting a profiler
+type ProfilerOption interface {
+	SetProfiler(*Profiler)
+}
+
+// StartTimeoutOption supports setting a time a start hook is given
+type StartTimeoutOption interface {
+	SetStartTimeout(time.Duration)
+}
+
+// StopTimeoutOption supports setting a time a stop hook is given
+type StopTimeoutOption interface {
+	SetStopTimeout(time.Duration)
+}
+
+// SignalOption supports setting signals an app stops on
+type SignalOption interface {
+	SetSignals(...os.Signal)
+}
+
+// CheckTimeoutOption supports setting a time a health check is given
+type CheckTimeoutOption interface {
+	SetCheckTimeout(time.Duration)
+}
+
+// RetryOption supports retrying failed constructors
+type RetryOption interface {
+	SetRetry(attempts int, backoff time.Duration)
+}
+
+// TimeoutOption supports limiting construction time
+type TimeoutOption interface {
+	SetTimeout(time.Duration)
+}
+
+// RecoverOption supports enabling panic recovery
+type RecoverOption interface {
+	SetRecover(bool)
+}
+
+// BindResultsOption supports binding returned values of a called function
+type BindResultsOption interface {
+	SetBindResults(...string)
+}
+
+// SettersOption supports calling setter methods of an instance
+type SettersOption interface {
+	SetSetters(...string)
+}
+
+// HooksOption supports calling post-construct hooks with any resolvable arguments
+type HooksOption interface {
+	SetHooks(bool)
+}
+
+// NamedArgsOption supports resolving function arguments by name
+type NamedArgsOption interface {
+	SetNamedArgs(...NamedKey)
+}
+
+// abstractionOption supports binding an instance under a type other than its own
+type abstractionOption interface {
+	setAbstraction(abstraction reflect.Type, caller string)
+}
+
 // WithName returns a NamingOption
 func WithName(names ...string) Option {
 	return func(o Options) {
@@ -50,11 +149,333 @@ func WithFill() Option {
 	}
 }
 
+// WithOptionalArgs returns an OptionalArgsOption
+func WithOptionalArgs() Option {
+	return func(o Options) {
+		if opt, ok := o.(OptionalArgsOption); ok {
+			opt.SetOptionalArgs(true)
+		}
+	}
+}
+
+// WithProfiles returns a ProfileOption
+func WithProfiles(profiles ...string) Option {
+	return func(o Options) {
+		if opt, ok := o.(ProfileOption); ok {
+			opt.SetProfiles(profiles...)
+		}
+	}
+}
+
+// WithProfilesFromEnv returns a ProfileOption with a comma separated list of profiles read from an environment variable
+func WithProfilesFromEnv(variable string) Option {
+	var profiles []string
+	for _, p := range strings.Split(os.Getenv(variable), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			profiles = append(profiles, p)
+		}
+	}
+
+	return WithProfiles(profiles...)
+}
+
+// WhenProfile returns a ProfileConditionOption. Profile prefixed with `!` matches when it is not active.
+func WhenProfile(profiles ...string) Option {
+	return func(o Options) {
+		if opt, ok := o.(ProfileConditionOption); ok {
+			opt.SetProfileCondition(profiles...)
+		}
+	}
+}
+
+// WithReinstantiate returns a ReinstantiateOption
+func WithReinstantiate() Option {
+	return func(o Options) {
+		if opt, ok := o.(ReinstantiateOption); ok {
+			opt.SetReinstantiate(true)
+		}
+	}
+}
+
+// WithObserver returns an ObserverOption
+func WithObserver(observers ...Observer) Option {
+	return func(o Options) {
+		if opt, ok := o.(ObserverOption); ok {
+			opt.AddObserver(observers...)
+		}
+	}
+}
+
+// WithProfiler returns a ProfilerOption
+func WithProfiler(p *Profiler) Option {
+	return func(o Options) {
+		if opt, ok := o.(ProfilerOption); ok {
+			opt.SetProfiler(p)
+		}
+	}
+}
+
+// WithStartTimeout returns a StartTimeoutOption, zero duration disables the timeout
+func WithStartTimeout(d time.Duration) Option {
+	return func(o Options) {
+		if opt, ok := o.(StartTimeoutOption); ok {
+			opt.SetStartTimeout(d)
+		}
+	}
+}
+
+// WithStopTimeout returns a StopTimeoutOption, zero duration disables the timeout
+func WithStopTimeout(d time.Duration) Option {
+	return func(o Options) {
+		if opt, ok := o.(StopTimeoutOption); ok {
+			opt.SetStopTimeout(d)
+		}
+	}
+}
+
+// WithSignals returns a SignalOption, no signals disable signal handling of App.Run()
+func WithSignals(signals ...os.Signal) Option {
+	return func(o Options) {
+		if opt, ok := o.(SignalOption); ok {
+			opt.SetSignals(signals...)
+		}
+	}
+}
+
+// WithCheckTimeout returns a CheckTimeoutOption, zero duration disables the timeout
+func WithCheckTimeout(d time.Duration) Option {
+	return func(o Options) {
+		if opt, ok := o.(CheckTimeoutOption); ok {
+			opt.SetCheckTimeout(d)
+		}
+	}
+}
+
+// WithRetry returns a RetryOption. Constructor and post-construct hook calls are made up to attempts times,
+// waiting for a jittered backoff that doubles after every failed attempt. Waiting stops when context.Context bound to the container is done.
+func WithRetry(attempts int, backoff time.Duration) Option {
+	return func(o Options) {
+		if opt, ok := o.(RetryOption); ok {
+			opt.SetRetry(attempts, backoff)
+		}
+	}
+}
+
+// WithTimeout returns a TimeoutOption. Constructor, Fill() and post-construct hook calls of a binding are limited with provided duration,
+// Hooks accepting context.Context receive a context with a deadline.
+func WithTimeout(d time.Duration) Option {
+	return func(o Options) {
+		if opt, ok := o.(TimeoutOption); ok {
+			opt.SetTimeout(d)
+		}
+	}
+}
+
+// WithRecover returns a RecoverOption. Panics raised while constructing bindings of a container or calling functions
+// with resolvers working against it are returned as *PanicError.
+func WithRecover() Option {
+	return func(o Options) {
+		if opt, ok := o.(RecoverOption); ok {
+			opt.SetRecover(true)
+		}
+	}
+}
+
+// WithBindResults returns a BindResultsOption. Values returned by a called function are bound as singletons into
+// the first container of a resolver following the naming rules of WithName().
+func WithBindResults(names ...string) Option {
+	return func(o Options) {
+		if opt, ok := o.(BindResultsOption); ok {
+			opt.SetBindResults(names...)
+		}
+	}
+}
+
+// WithSetters returns a SettersOption. Exported methods of an instance are called with arguments resolved the same way
+// function arguments are. A method can be listed by its name, then it has to exist and its arguments have to be resolvable,
+// or by a regular expression matching whole method names, then methods with unresolvable arguments are skipped.
+// DefaultSetterPattern is used if nothing is provided.
+func WithSetters(methods ...string) Option {
+	return func(o Options) {
+		if opt, ok := o.(SettersOption); ok {
+			opt.SetSetters(methods...)
+		}
+	}
+}
+
+// WithHooks returns a HooksOption. Construct() and Init() methods of instances that return nothing or a single error
+// are called with arguments resolved the same way Call() resolves them, not only the ones implementing Constructor and Initializer.
+func WithHooks() Option {
+	return func(o Options) {
+		if opt, ok := o.(HooksOption); ok {
+			opt.SetHooks(true)
+		}
+	}
+}
+
+// WithNamedArgs returns a NamedArgsOption. Function arguments are resolved by names of provided keys,
+// keys are matched with arguments of the same type in order.
+func WithNamedArgs(keys ...NamedKey) Option {
+	return func(o Options) {
+		if opt, ok := o.(NamedArgsOption); ok {
+			opt.SetNamedArgs(keys...)
+		}
+	}
+}
+
+// bindAs returns an abstractionOption, it's used by Key[T] to bind values as T and to report its own caller
+func bindAs(abstraction reflect.Type, caller string) Option {
+	return func(o Options) {
+		if opt, ok := o.(abstractionOption); ok {
+			opt.setAbstraction(abstraction, caller)
+		}
+	}
+}
+
+// options for cloning containers
+type cloneOptions struct {
+	reinstantiate bool
+}
+
+func newCloneOptions(opts []Option) (out cloneOptions) {
+	for _, o := range opts {
+		out.Apply(o)
+	}
+
+	return
+}
+
+// Apply implements Options interface
+func (o *cloneOptions) Apply(opt Option) {
+	opt(o)
+}
+
+// SetReinstantiate implements ReinstantiateOption interface
+func (o *cloneOptions) SetReinstantiate(f bool) {
+	o.reinstantiate = f
+}
+
+// options for creating containers
+type containerOptions struct {
+	profiles  []string
+	observers []Observer
+	profiler  *Profiler
+	recover   bool
+}
+
+func newContainerOptions(opts []Option) (out containerOptions) {
+	for _, o := range opts {
+		out.Apply(o)
+	}
+
+	return
+}
+
+// Apply implements Options interface
+func (o *containerOptions) Apply(opt Option) {
+	opt(o)
+}
+
+// SetProfiles implements ProfileOption interface
+func (o *containerOptions) SetProfiles(profiles ...string) {
+	o.profiles = profiles
+}
+
+// AddObserver implements ObserverOption interface
+func (o *containerOptions) AddObserver(observers ...Observer) {
+	o.observers = append(o.observers, observers...)
+}
+
+// SetProfiler implements ProfilerOption interface
+func (o *containerOptions) SetProfiler(p *Profiler) {
+	o.profiler = p
+}
+
+// SetRecover implements RecoverOption interface
+func (o *containerOptions) SetRecover(f bool) {
+	o.recover = f
+}
+
+// ConditionalOption supports setting conditions for a binding
+type ConditionalOption interface {
+	AddCondition(Condition)
+}
+
+// Condition decides whether a conditional binding has to be registered on Container.Build()
+type Condition interface {
+	Met(Container) bool
+	String() string
+}
+
+// IfMissing returns a ConditionalOption. Binding is registered on Container.Build() only if the container has no binding of T with provided name.
+func IfMissing[T any](name ...string) Option {
+	return ifBinding[T](false, name)
+}
+
+// IfPresent returns a ConditionalOption. Binding is registered on Container.Build() only if the container has a binding of T with provided name.
+func IfPresent[T any](name ...string) Option {
+	return ifBinding[T](true, name)
+}
+
+func ifBinding[T any](present bool, name []string) Option {
+	var cond = bindCondition{
+		abstraction: reflect.TypeOf((*T)(nil)).Elem(),
+		name:        DefaultBindName,
+		present:     present,
+	}
+
+	if len(name) > 0 {
+		cond.name = name[0]
+	}
+
+	return func(o Options) {
+		if opt, ok := o.(ConditionalOption); ok {
+			opt.AddCondition(cond)
+		}
+	}
+}
+
+// bindCondition requires presence or absence of another binding
+type bindCondition struct {
+	abstraction reflect.Type
+	name        string
+	present     bool
+}
+
+// Met implements Condition interface
+func (c bindCondition) Met(cnt Container) bool {
+	var list, _ = cnt.ListBindings(c.abstraction)
+	var _, has = list[c.name]
+
+	return has == c.present
+}
+
+// String implements fmt.Stringer interface
+func (c bindCondition) String() string {
+	if c.present {
+		return fmt.Sprintf("if present %s [%s]", c.abstraction.String(), c.name)
+	}
+
+	return fmt.Sprintf("if missing %s [%s]", c.abstraction.String(), c.name)
+}
+
 // options for binding implementations into container
 type bindOptions struct {
-	factory bool
-	fill    bool
-	names   []string
+	factory        bool
+	implementation bool
+	fill           bool
+	optionalArgs   bool
+	namedArgs      []NamedKey
+	hooks          bool
+	abstraction    reflect.Type // type an implementation is bound under instead of its own one
+	setters        *setterPolicy
+	retry          retryPolicy
+	timeout        time.Duration
+	building       bool // binding is applied by Container.Build(), conditions were already evaluated
+	caller         string
+	names          []string
+	profiles       []string
+	conditions     []Condition
 }
 
 func newBindOptions(opts []Option) (out bindOptions) {
@@ -70,6 +491,28 @@ func (o *bindOptions) Apply(opt Option) {
 	opt(o)
 }
 
+// kind returns a kind of binding options describe
+func (o bindOptions) kind() BindingKind {
+	switch {
+	case o.factory:
+		return KindFactory
+
+	case o.implementation:
+		return KindImplementation
+	}
+
+	return KindSingleton
+}
+
+// bindingType returns a type an implementation is bound under
+func (o bindOptions) bindingType(implementation any) reflect.Type {
+	if o.abstraction != nil {
+		return o.abstraction
+	}
+
+	return reflect.TypeOf(implementation)
+}
+
 // SetName implements NamingOption interface
 func (o *bindOptions) SetName(names ...string) {
 	o.names = names
@@ -80,6 +523,51 @@ func (o *bindOptions) SetFill(f bool) {
 	o.fill = f
 }
 
+// SetOptionalArgs implements OptionalArgsOption interface
+func (o *bindOptions) SetOptionalArgs(f bool) {
+	o.optionalArgs = f
+}
+
+// SetHooks implements HooksOption interface
+func (o *bindOptions) SetHooks(f bool) {
+	o.hooks = f
+}
+
+// SetNamedArgs implements NamedArgsOption interface
+func (o *bindOptions) SetNamedArgs(keys ...NamedKey) {
+	o.namedArgs = keys
+}
+
+// setAbstraction implements abstractionOption interface
+func (o *bindOptions) setAbstraction(abstraction reflect.Type, caller string) {
+	o.abstraction, o.caller = abstraction, caller
+}
+
+// SetSetters implements SettersOption interface
+func (o *bindOptions) SetSetters(methods ...string) {
+	o.setters = newSetterPolicy(methods)
+}
+
+// SetRetry implements RetryOption interface
+func (o *bindOptions) SetRetry(attempts int, backoff time.Duration) {
+	o.retry = retryPolicy{attempts: attempts, backoff: backoff}
+}
+
+// SetTimeout implements TimeoutOption interface
+func (o *bindOptions) SetTimeout(d time.Duration) {
+	o.timeout = d
+}
+
+// SetProfileCondition implements ProfileConditionOption interface
+func (o *bindOptions) SetProfileCondition(profiles ...string) {
+	o.profiles = profiles
+}
+
+// AddCondition implements ConditionalOption interface
+func (o *bindOptions) AddCondition(c Condition) {
+	o.conditions = append(o.conditions, c)
+}
+
 // options for resolving abstractions
 type resolveOptions struct {
 	name string
@@ -106,9 +594,37 @@ func (o *resolveOptions) SetName(names ...string) {
 	}
 }
 
-// options for resolving abstractions
+// options for filling receivers
+type fillOptions struct {
+	setters *setterPolicy
+}
+
+func newFillOptions(opts []Option) (out fillOptions) {
+	for _, o := range opts {
+		out.Apply(o)
+	}
+
+	return
+}
+
+// Apply implements Options interface
+func (o *fillOptions) Apply(opt Option) {
+	opt(o)
+}
+
+// SetSetters implements SettersOption interface
+func (o *fillOptions) SetSetters(methods ...string) {
+	o.setters = newSetterPolicy(methods)
+}
+
+// options for calling functions
 type callOptions struct {
-	returns []any
+	optionalArgs bool
+	bindResults  bool
+	caller       string
+	returns      []any
+	resultNames  []string
+	namedArgs    []NamedKey
 }
 
 func newCallOptions(opts []Option) (out callOptions) {
@@ -128,3 +644,82 @@ func (o *callOptions) Apply(opt Option) {
 func (o *callOptions) SetReturn(returns ...any) {
 	o.returns = returns
 }
+
+// SetOptionalArgs implements OptionalArgsOption interface
+func (o *callOptions) SetOptionalArgs(f bool) {
+	o.optionalArgs = f
+}
+
+// SetNamedArgs implements NamedArgsOption interface
+func (o *callOptions) SetNamedArgs(keys ...NamedKey) {
+	o.namedArgs = keys
+}
+
+// SetBindResults implements BindResultsOption interface
+func (o *callOptions) SetBindResults(names ...string) {
+	o.bindResults = true
+	o.resultNames = names
+}
+
+// options for creating apps
+type appOptions struct {
+	startTimeout time.Duration
+	stopTimeout  time.Duration
+	signals      []os.Signal
+}
+
+func newAppOptions(opts []Option) (out appOptions) {
+	out.startTimeout = DefaultStartTimeout
+	out.stopTimeout = DefaultStopTimeout
+	out.signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+	for _, o := range opts {
+		out.Apply(o)
+	}
+
+	return
+}
+
+// Apply implements Options interface
+func (o *appOptions) Apply(opt Option) {
+	opt(o)
+}
+
+// SetStartTimeout implements StartTimeoutOption interface
+func (o *appOptions) SetStartTimeout(d time.Duration) {
+	o.startTimeout = d
+}
+
+// SetStopTimeout implements StopTimeoutOption interface
+func (o *appOptions) SetStopTimeout(d time.Duration) {
+	o.stopTimeout = d
+}
+
+// SetSignals implements SignalOption interface
+func (o *appOptions) SetSignals(signals ...os.Signal) {
+	o.signals = signals
+}
+
+// options for running health checks
+type healthOptions struct {
+	timeout time.Duration
+}
+
+func newHealthOptions(opts []Option) (out healthOptions) {
+	out.timeout = DefaultCheckTimeout
+	for _, o := range opts {
+		out.Apply(o)
+	}
+
+	return
+}
+
+// Apply implements Options interface
+func (o *healthOptions) Apply(opt Option) {
+	opt(o)
+}
+
+// SetCheckTimeout implements CheckTimeoutOption interface
+func (o *healthOptions) SetCheckTimeout(d time.Duration) {
+	o.timeout = d
+}
@@ -0,0 +1,56 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+	"runtime/debug"
+)
+
+// PanicError is returned instead of a panic raised by a constructor, a post-construct hook, a called function
+// or reflection itself when recovery is enabled with WithRecover()
+type PanicError struct {
+	Value       any          // recovered value
+	Stack       []byte       // stack trace of the panicking goroutine
+	Abstraction reflect.Type // abstraction of the binding being constructed, a type of the function or the receiver otherwise
+	Name        string       // name of the binding being constructed
+	Caller      string       // location the binding was declared at
+}
+
+// Error implements error interface
+func (e *PanicError) Error() string {
+	if e.Caller == "" {
+		return fmt.Sprintf("di: panic in %s: %v", e.Abstraction.String(), e.Value)
+	}
+
+	return fmt.Sprintf("di: panic constructing %s [%s] declared at %s: %v", e.Abstraction.String(), e.Name, e.Caller, e.Value)
+}
+
+// Unwrap returns a recovered value if it is an error
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+
+	return nil
+}
+
+// recoverPanic converts a panic into a *PanicError, it must be deferred directly
+func recoverPanic(abstraction reflect.Type, name, caller string, err *error) {
+	var v = recover()
+	if v == nil {
+		return
+	}
+
+	*err = &PanicError{Value: v, Stack: debug.Stack(), Abstraction: abstraction, Name: name, Caller: caller}
+}
+
+// recovers checks whether any of resolver containers has recovery enabled
+func (self *resolver) recovers() bool {
+	for _, cnt := range self.containers {
+		if c, ok := cnt.(*container); ok && c.recover {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,100 @@
+package di_test
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/HnH/di"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestPanicSuite(t *testing.T) {
+	suite.Run(t, new(PanicSuite))
+}
+
+type PanicSuite struct {
+	suite.Suite
+}
+
+func (suite *PanicSuite) TestSingleton() {
+	var c = di.NewContainer(di.WithRecover())
+
+	var err = c.Singleton(func() Shape { panic("boom") }, di.WithName("broken"))
+
+	var panicErr *di.PanicError
+	suite.Require().True(errors.As(err, &panicErr))
+	suite.Require().Equal("boom", panicErr.Value)
+	suite.Require().Contains(string(panicErr.Stack), "panic_test.go")
+	suite.Require().Equal(reflect.TypeOf((*Shape)(nil)).Elem(), panicErr.Abstraction)
+	suite.Require().Equal("broken", panicErr.Name)
+	suite.Require().Contains(panicErr.Caller, "panic_test.go")
+	suite.Require().Regexp(`^di: panic constructing di_test.Shape \[broken\] declared at .+panic_test.go:\d+: boom$`, err.Error())
+	suite.Require().NoError(errors.Unwrap(err))
+
+	// recovery is opt-in
+	suite.Require().Panics(func() { _ = di.NewContainer().Singleton(func() Shape { panic("boom") }) })
+}
+
+func (suite *PanicSuite) TestFactory() {
+	var (
+		c = di.NewContainer(di.WithRecover())
+		r = di.NewResolver(c)
+	)
+
+	suite.Require().NoError(c.Factory(func() Shape {
+		var m map[string]int
+		m["a"] = 1
+
+		return newCircle()
+	}))
+
+	suite.Require().NoError(c.Factory(func(Shape) Database { return newMySQL() }, di.WithTimeout(time.Second)))
+
+	// panic in a nested binding resolved by a constructor running with a timeout
+	var db Database
+	var err = r.Resolve(&db)
+
+	var panicErr *di.PanicError
+	suite.Require().True(errors.As(err, &panicErr))
+	suite.Require().Equal(reflect.TypeOf((*Shape)(nil)).Elem(), panicErr.Abstraction)
+
+	var runtimeErr runtime.Error
+	suite.Require().True(errors.As(err, &runtimeErr))
+}
+
+// misbound lists bindings of another abstraction, so that resolving them makes reflect panic
+type misbound struct {
+	di.Container
+	from, to reflect.Type
+}
+
+func (m misbound) ListBindings(t reflect.Type) (map[string]di.Binding, error) {
+	if t == m.to {
+		return m.Container.ListBindings(m.from)
+	}
+
+	return m.Container.ListBindings(t)
+}
+
+func (suite *PanicSuite) TestReflect() {
+	var c = di.NewContainer(di.WithRecover())
+	suite.Require().NoError(c.Factory(newMySQL))
+
+	// binding of a wrong type makes reflect panic
+	var r = di.NewResolver(misbound{Container: c, from: reflect.TypeOf((*Database)(nil)).Elem(), to: reflect.TypeOf((*Shape)(nil)).Elem()}, c)
+
+	var s Shape
+	var err = r.Resolve(&s)
+	suite.Require().IsType(&di.PanicError{}, err)
+	suite.Require().Contains(err.Error(), "di: panic in *di_test.Shape: reflect.Set: value of type *di_test.MySQL is not assignable to type di_test.Shape")
+
+	err = r.Call(func(Shape) {})
+	suite.Require().IsType(&di.PanicError{}, err)
+	suite.Require().Contains(err.Error(), "di: panic in func(di_test.Shape): reflect: Call using *di_test.MySQL as type di_test.Shape")
+
+	err = r.Call(func() { panic(errors.New("called")) })
+	suite.Require().EqualError(errors.Unwrap(err), "called")
+}
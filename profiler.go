@@ -0,0 +1,241 @@
+package di
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Profiler records wall time of singleton constructors, factory methods and post-construct hook calls.
+// Calls made while another one is in progress are nested under it, so time spent on resolving dependencies is attributed
+// to a dependant. Nesting is tracked per profiler rather than per goroutine, so profiling concurrent resolutions gives approximate results.
+type Profiler struct {
+	root  *profileNode
+	stack []*profileNode
+	lock  sync.Mutex
+}
+
+// ProfileEntry is an aggregated profile of a single function
+type ProfileEntry struct {
+	Name  string        // constructor function name or a type name followed by `.Construct`
+	Calls int           // number of calls
+	Total time.Duration // time spent including nested calls
+	Self  time.Duration // time spent excluding nested calls
+}
+
+// profileNode is a node of a call tree
+type profileNode struct {
+	name     string
+	calls    int
+	total    time.Duration
+	children map[string]*profileNode
+	order    []*profileNode // children in order of the first call
+}
+
+// NewProfiler creates an empty Profiler
+func NewProfiler() *Profiler {
+	return &Profiler{root: newProfileNode("")}
+}
+
+func newProfileNode(name string) *profileNode {
+	return &profileNode{name: name, children: make(map[string]*profileNode)}
+}
+
+// self returns time spent in the node excluding its children
+func (self *profileNode) self() time.Duration {
+	var out = self.total
+	for _, child := range self.order {
+		out -= child.total
+	}
+
+	if out < 0 {
+		return 0
+	}
+
+	return out
+}
+
+// enter starts a call nested under the current one and returns a function that finishes it
+func (self *Profiler) enter(name string) func() {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	var parent = self.root
+	if len(self.stack) > 0 {
+		parent = self.stack[len(self.stack)-1]
+	}
+
+	var node, ok = parent.children[name]
+	if !ok {
+		node = newProfileNode(name)
+		parent.children[name] = node
+		parent.order = append(parent.order, node)
+	}
+
+	self.stack = append(self.stack, node)
+
+	var start = time.Now()
+
+	return func() {
+		var elapsed = time.Since(start)
+
+		self.lock.Lock()
+		defer self.lock.Unlock()
+
+		node.calls++
+		node.total += elapsed
+
+		for i := len(self.stack) - 1; i >= 0; i-- {
+			if self.stack[i] == node {
+				self.stack = append(self.stack[:i], self.stack[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// Report returns profiles aggregated by function and sorted by total time, the slowest go first
+func (self *Profiler) Report() []ProfileEntry {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	var (
+		index = make(map[string]int)
+		out   []ProfileEntry
+		walk  func(node *profileNode, path map[string]bool)
+	)
+
+	walk = func(node *profileNode, path map[string]bool) {
+		for _, child := range node.order {
+			var i, ok = index[child.name]
+			if !ok {
+				i = len(out)
+				index[child.name] = i
+				out = append(out, ProfileEntry{Name: child.name})
+			}
+
+			out[i].Calls += child.calls
+			out[i].Self += child.self()
+
+			// recursive calls are already accounted in the total time of the outermost one
+			var seen = path[child.name]
+			if !seen {
+				out[i].Total += child.total
+			}
+
+			path[child.name] = true
+			walk(child, path)
+
+			if !seen {
+				delete(path, child.name)
+			}
+		}
+	}
+
+	walk(self.root, make(map[string]bool))
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Total != out[j].Total {
+			return out[i].Total > out[j].Total
+		}
+
+		return out[i].Name < out[j].Name
+	})
+
+	return out
+}
+
+// WriteReport writes a report as a table sorted by total time
+func (self *Profiler) WriteReport(w io.Writer) error {
+	var tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "TOTAL\tSELF\tCALLS\tNAME")
+	for _, e := range self.Report() {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\n", e.Total, e.Self, e.Calls, e.Name)
+	}
+
+	return tw.Flush()
+}
+
+// WriteFolded writes call stacks in folded format consumed by flame graph tools: frames separated by semicolons
+// followed by self time in microseconds, e.g. `main.newService;main.newDatabase 1520`
+func (self *Profiler) WriteFolded(w io.Writer) error {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	var (
+		err  error
+		walk func(node *profileNode, stack []string)
+	)
+
+	walk = func(node *profileNode, stack []string) {
+		for _, child := range node.order {
+			var path = append(stack[:len(stack):len(stack)], strings.ReplaceAll(child.name, ";", ":"))
+			if err == nil {
+				_, err = fmt.Fprintf(w, "%s %d\n", strings.Join(path, ";"), child.self().Microseconds())
+			}
+
+			walk(child, path)
+		}
+	}
+
+	walk(self.root, nil)
+
+	return err
+}
+
+// Reset drops everything recorded so far
+func (self *Profiler) Reset() {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	self.root = newProfileNode("")
+	self.stack = nil
+}
+
+// profilers is a list of profilers calls are recorded to
+type profilers []*Profiler
+
+// contains checks whether a profiler is in the list
+func (self profilers) contains(p *Profiler) bool {
+	for _, item := range self {
+		if item == p {
+			return true
+		}
+	}
+
+	return false
+}
+
+// enter starts a call in each of profilers and returns a function that finishes it
+func (self profilers) enter(name string) func() {
+	if len(self) == 0 {
+		return func() {}
+	}
+
+	var exits = make([]func(), len(self))
+	for i, p := range self {
+		exits[i] = p.enter(name)
+	}
+
+	return func() {
+		for i := len(exits) - 1; i >= 0; i-- {
+			exits[i]()
+		}
+	}
+}
+
+// funcName returns a fully qualified name of a function
+func funcName(function any) string {
+	if fn := runtime.FuncForPC(reflect.ValueOf(function).Pointer()); fn != nil {
+		return fn.Name()
+	}
+
+	return reflect.TypeOf(function).String()
+}
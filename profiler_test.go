@@ -0,0 +1,124 @@
+package di_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HnH/di"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestProfilerSuite(t *testing.T) {
+	suite.Run(t, new(ProfilerSuite))
+}
+
+type ProfilerSuite struct {
+	suite.Suite
+}
+
+type slowDatabase struct{}
+
+func (slowDatabase) Connect() bool { return true }
+
+func (*slowDatabase) Construct(context.Context) error {
+	time.Sleep(2 * time.Millisecond)
+	return nil
+}
+
+func newSlowDatabase() Database {
+	time.Sleep(5 * time.Millisecond)
+	return &slowDatabase{}
+}
+
+func newSlowShape(Database) Shape {
+	time.Sleep(time.Millisecond)
+	return &Circle{}
+}
+
+func (suite *ProfilerSuite) TestProfile() {
+	var (
+		p = di.NewProfiler()
+		c = di.NewContainer(di.WithProfiler(p))
+	)
+
+	suite.Require().NoError(c.Singleton(context.Background))
+	suite.Require().NoError(c.Factory(newSlowDatabase))
+	suite.Require().NoError(c.Singleton(newSlowShape))
+	suite.Require().NoError(di.NewResolver(c).Call(func(Database) {}))
+
+	var report = entries(p.Report())
+	suite.Require().Len(report, 4)
+
+	var db = report["github.com/HnH/di_test.newSlowDatabase"]
+	suite.Require().Equal(2, db.Calls)
+	suite.Require().Equal(db.Total, db.Self)
+
+	var shape = report["github.com/HnH/di_test.newSlowShape"]
+	suite.Require().Equal(1, shape.Calls)
+	suite.Require().LessOrEqual(shape.Self, shape.Total)
+	suite.Require().GreaterOrEqual(shape.Total-shape.Self, 5*time.Millisecond) // nested database constructor
+
+	var construct = report["*di_test.slowDatabase.Construct"]
+	suite.Require().Equal(2, construct.Calls)
+	suite.Require().Equal(construct.Total, construct.Self)
+
+	suite.Require().Equal(1, report["context.Background"].Calls)
+
+	var buf bytes.Buffer
+	suite.Require().NoError(p.WriteFolded(&buf))
+
+	var stacks []string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		stacks = append(stacks, line[:strings.LastIndex(line, " ")])
+	}
+
+	suite.Require().Equal([]string{
+		"context.Background",
+		"github.com/HnH/di_test.newSlowShape",
+		"github.com/HnH/di_test.newSlowShape;github.com/HnH/di_test.newSlowDatabase",
+		"github.com/HnH/di_test.newSlowShape;*di_test.slowDatabase.Construct",
+		"github.com/HnH/di_test.newSlowDatabase",
+		"*di_test.slowDatabase.Construct",
+	}, stacks)
+
+	buf.Reset()
+	suite.Require().NoError(p.WriteReport(&buf))
+	suite.Require().True(strings.HasPrefix(buf.String(), "TOTAL"))
+	suite.Require().Contains(buf.String(), "github.com/HnH/di_test.newSlowShape")
+
+	p.Reset()
+	suite.Require().Empty(p.Report())
+}
+
+func (suite *ProfilerSuite) TestSharedProfiler() {
+	var (
+		p     = di.NewProfiler()
+		lib   = di.NewContainer(di.WithProfiler(p))
+		app   = di.NewContainer(di.WithProfiler(p))
+		clone di.Container
+		err   error
+	)
+
+	suite.Require().NoError(lib.Singleton(context.Background))
+	suite.Require().NoError(lib.Factory(newSlowDatabase))
+	suite.Require().NoError(di.NewResolver(app, lib).Call(func(Database) {}))
+
+	clone, err = lib.Clone()
+	suite.Require().NoError(err)
+	suite.Require().NoError(di.NewResolver(clone).Call(func(Database) {}))
+
+	suite.Require().Equal(2, entries(p.Report())["github.com/HnH/di_test.newSlowDatabase"].Calls)
+}
+
+// entries indexes profile entries by name, so assertions do not depend on the order of wall-clock times
+func entries(report []di.ProfileEntry) map[string]di.ProfileEntry {
+	var out = make(map[string]di.ProfileEntry, len(report))
+	for _, e := range report {
+		out[e.Name] = e
+	}
+
+	return out
+}
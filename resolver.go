@@ -1,10 +1,12 @@
 package di
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 	"unsafe"
 )
 
@@ -21,23 +23,35 @@ func NewResolver(containers ...Container) Resolver {
 
 // Resolver implements methods for several implementation resolution scenarios
 type Resolver interface {
+	Containers() []Container
 	With(implementations ...any) Resolver
+	Observe(observers ...Observer) Resolver
+	Timeout(d time.Duration) Resolver
 	Resolve(receiver any, opts ...Option) error
 	Call(function any, opts ...Option) error
-	Fill(receiver any) error
+	Fill(receiver any, opts ...Option) error
 }
 
 type resolver struct {
 	containers      []Container
 	implementations []any
+	observers       observers
+	timeout         time.Duration   // default timeout of bindings without their own one
+	ctx             context.Context // context passed instead of a bound one, carries a deadline of a binding timeout
 }
 
 func (self *resolver) getBinding(abstraction reflect.Type, name string) (bnd Binding, err error) {
+	if self.ctx != nil && abstraction == contextType && name == DefaultBindName {
+		return Binding{instance: self.ctx, abstraction: abstraction, name: name}, nil
+	}
+
 	// look in with() implementation list
 	for _, inst := range self.implementations {
 		if reflect.TypeOf(inst).AssignableTo(abstraction) && name == DefaultBindName {
 			return Binding{
-				instance: inst,
+				instance:    inst,
+				abstraction: abstraction,
+				name:        name,
 			}, nil
 		}
 	}
@@ -67,43 +81,158 @@ func (self *resolver) resolveBinding(abstraction reflect.Type, name string) (any
 	return self.resolveBindingInstance(bnd)
 }
 
-func (self *resolver) resolveBindingInstance(bnd Binding) (any, error) {
+func (self *resolver) resolveBindingInstance(bnd Binding) (out any, err error) {
+	bnd.resolved()
+
+	var obs = self.allObservers()
+	if len(obs) == 0 {
+		return self.instantiate(bnd, obs)
+	}
+
+	var event, start = bnd.event(), time.Now()
+	obs.notify(func(o Observer) { o.OnResolveStart(event) })
+
+	out, err = self.instantiate(bnd, obs)
+
+	event.Duration, event.Err = time.Since(start), err
+	obs.notify(func(o Observer) { o.OnResolveEnd(event) })
+
+	if err != nil {
+		obs.notify(func(o Observer) { o.OnError(event) })
+	}
+
+	return
+}
+
+// instantiate returns an instance of a singleton binding or creates a new one with a factory method
+func (self *resolver) instantiate(bnd Binding, obs observers) (any, error) {
 	// Is binding already instantiated?
 	if bnd.instance != nil {
 		return bnd.instance, nil
 	}
 
 	// Or we need to call a factory method?
-	var out, err = self.invoke(bnd.factory)
+	var (
+		start   = time.Now()
+		timeout = bnd.timeout
+		out     []reflect.Value
+	)
+
+	if timeout == 0 {
+		timeout = self.timeout
+	}
+
+	var setters []string
+	var err = self.guard(bnd.abstraction, bnd.name, bnd.caller, timeout, func(rsl *resolver) (err error) {
+		var exit = rsl.profilers().enter(funcName(bnd.factory))
+		err = bnd.retry.do(rsl.context(), func() (err error) {
+			out, err = rsl.invoke(bnd.factory, bnd.optionalArgs, bnd.namedArgs)
+			return
+		})
+
+		if exit(); err != nil {
+			return
+		}
+
+		setters, err = rsl.construct(out[0].Interface(), bnd)
+		return
+	})
+
 	if err != nil {
 		return nil, err
 	}
 
+	if len(obs) > 0 {
+		var event = bnd.event()
+		event.Duration, event.Setters = time.Since(start), setters
+		obs.notify(func(o Observer) { o.OnConstruct(event) })
+	}
+
+	return out[0].Interface(), nil
+}
+
+// construct fills an instance and calls its setters if the binding requests it, then calls its post-construct hooks:
+// Construct() and Init() methods, see postConstructHooks. Names of the called setters are returned.
+func (self *resolver) construct(instance any, bnd Binding) (setters []string, err error) {
 	if bnd.fill {
-		if err = self.Fill(out[0].Interface()); err != nil {
-			return nil, err
+		if err = self.Fill(instance); err != nil {
+			return
 		}
 	}
 
-	if t, ok := out[0].Interface().(Constructor); ok {
-		if _, err = self.invoke(t.Construct); err != nil {
-			return nil, err
+	if setters, err = self.injectSetters(instance, bnd.setters); err != nil {
+		return
+	}
+
+	for _, hook := range hooks(instance, bnd.hooks) {
+		var exit = self.profilers().enter(hook.name)
+		err = bnd.retry.do(self.context(), func() (err error) {
+			_, err = self.invoke(hook.method, false, nil)
+			return
+		})
+
+		if exit(); err != nil {
+			return
 		}
 	}
 
-	return out[0].Interface(), nil
+	return
 }
 
-// arguments returns container-resolved arguments of a function.
-func (self *resolver) arguments(function any) ([]reflect.Value, error) {
+// arguments returns container-resolved arguments of a function. Arguments matching named keys are resolved by their names.
+// If optionalArgs is set, arguments without a binding are passed as zero values instead of returning an error.
+func (self *resolver) arguments(function any, optionalArgs bool, keys []NamedKey) ([]reflect.Value, error) {
 	var (
-		ref  = reflect.TypeOf(function)
-		args = make([]reflect.Value, ref.NumIn())
+		ref        = reflect.TypeOf(function)
+		args       = make([]reflect.Value, ref.NumIn())
+		names, err = argumentNames(ref, keys)
 	)
 
+	if err != nil {
+		return nil, err
+	}
+
 	for i := 0; i < ref.NumIn(); i++ {
-		var instance, err = self.resolveBinding(ref.In(i), DefaultBindName)
+		if isOptional(ref.In(i)) {
+			var arg, err = self.optionalArgument(ref.In(i), names[i])
+			if err != nil {
+				return nil, err
+			}
+
+			args[i] = arg
+			continue
+		}
+
+		var bnd, err = self.getBinding(ref.In(i), names[i])
+
+		// variadic, slice and map arguments without a binding of their own are filled with all bindings of the element type
+		if err != nil && names[i] == DefaultBindName && isCollection(ref.In(i)) {
+			var items, collectErr = self.collect(ref.In(i).Elem(), ref.In(i).Kind() == reflect.Map)
+			if collectErr != nil {
+				return nil, collectErr
+			}
+
+			if len(items) > 0 || ref.IsVariadic() && i == ref.NumIn()-1 {
+				args[i] = collection(ref.In(i), items)
+				continue
+			}
+		}
+
 		if err != nil {
+			if optionalArgs {
+				args[i] = reflect.Zero(ref.In(i))
+				continue
+			}
+
+			if names[i] != DefaultBindName {
+				err = fmt.Errorf("%w [%s]", err, names[i])
+			}
+
+			return nil, err
+		}
+
+		var instance any
+		if instance, err = self.resolveBindingInstance(bnd); err != nil {
 			return nil, err
 		}
 
@@ -113,14 +242,45 @@ func (self *resolver) arguments(function any) ([]reflect.Value, error) {
 	return args, nil
 }
 
+// optionalArgument builds an Optional[T] argument which is marked as present only if T has a binding with provided name.
+func (self *resolver) optionalArgument(t reflect.Type, name string) (reflect.Value, error) {
+	var (
+		arg      = reflect.New(t)
+		bnd, err = self.getBinding(arg.Elem().Interface().(optional).abstraction(), name)
+	)
+
+	if err != nil {
+		return arg.Elem(), nil
+	}
+
+	var instance any
+	if instance, err = self.resolveBindingInstance(bnd); err != nil {
+		return arg.Elem(), err
+	}
+
+	arg.Interface().(optionalSetter).set(instance)
+
+	return arg.Elem(), nil
+}
+
+// callFunction calls a function, the last argument of a variadic function is passed as a slice
+func callFunction(function any, args []reflect.Value) []reflect.Value {
+	var fn = reflect.ValueOf(function)
+	if fn.Type().IsVariadic() {
+		return fn.CallSlice(args)
+	}
+
+	return fn.Call(args)
+}
+
 // invoke calls a function and returns the yielded values.
-func (self *resolver) invoke(function any) (out []reflect.Value, err error) {
+func (self *resolver) invoke(function any, optionalArgs bool, keys []NamedKey) (out []reflect.Value, err error) {
 	var args []reflect.Value
-	if args, err = self.arguments(function); err != nil {
+	if args, err = self.arguments(function, optionalArgs, keys); err != nil {
 		return
 	}
 
-	out = reflect.ValueOf(function).Call(args)
+	out = callFunction(function, args)
 	// if there is something returned and the last value is error and it's not nil then return it
 	if len(out) > 0 && isError(out[len(out)-1].Type()) && !out[len(out)-1].IsNil() {
 		return nil, out[len(out)-1].Interface().(error)
@@ -129,20 +289,87 @@ func (self *resolver) invoke(function any) (out []reflect.Value, err error) {
 	return
 }
 
+// Containers returns a list of containers resolver works against
+func (self *resolver) Containers() []Container {
+	var out = make([]Container, len(self.containers))
+	copy(out, self.containers)
+
+	return out
+}
+
+// allObservers lists observers of the resolver followed by observers of its containers
+func (self *resolver) allObservers() observers {
+	var out = self.observers
+	for _, cnt := range self.containers {
+		if c, ok := cnt.(*container); ok && len(c.observers) > 0 {
+			out = append(out[:len(out):len(out)], c.observers...)
+		}
+	}
+
+	return out
+}
+
+// profilers lists distinct profilers of resolver containers
+func (self *resolver) profilers() profilers {
+	var out profilers
+	for _, cnt := range self.containers {
+		if c, ok := cnt.(*container); ok && c.profiler != nil && !out.contains(c.profiler) {
+			out = append(out, c.profiler)
+		}
+	}
+
+	return out
+}
+
+// Observe returns a copy of the resolver that notifies provided observers in addition to the existing ones
+func (self *resolver) Observe(observers ...Observer) Resolver {
+	var res = self.derive()
+	res.observers = append(self.observers[:len(self.observers):len(self.observers)], observers...)
+
+	return res
+}
+
 // With takes a list of instantiated implementations and tries to use them in resolving scenarios
 func (self *resolver) With(implementations ...any) Resolver {
-	var res = &resolver{
-		containers:      make([]Container, len(self.containers)),
-		implementations: implementations, // this is required for us to be able to resolve already existing implementations to abstract types (interfaces)
-	}
+	var res = self.derive()
+	res.implementations = implementations // this is required for us to be able to resolve already existing implementations to abstract types (interfaces)
 
-	copy(res.containers, self.containers)
+	return res
+}
+
+// Timeout returns a copy of the resolver that limits construction time of factory bindings without their own timeout.
+// Singletons are constructed when they are bound, so the timeout doesn't apply to them.
+func (self *resolver) Timeout(d time.Duration) Resolver {
+	var res = self.derive()
+	res.timeout = d
 
 	return res
 }
 
+// derive returns a copy of the resolver keeping its settings and a bound context
+func (self *resolver) derive() *resolver {
+	var res = *self
+	res.containers = make([]Container, len(self.containers))
+	copy(res.containers, self.containers)
+
+	return &res
+}
+
 // Call takes a function, builds a list of arguments for it from the available bindings, calls it and returns a result.
 func (self *resolver) Call(function any, opts ...Option) error {
+	var err = self.call(function, opts, callerLocation(2))
+	if err != nil {
+		self.allObservers().notify(func(o Observer) { o.OnError(Event{Abstraction: reflect.TypeOf(function), Err: err}) })
+	}
+
+	return err
+}
+
+func (self *resolver) call(function any, opts []Option, caller string) (err error) {
+	if self.recovers() {
+		defer recoverPanic(reflect.TypeOf(function), "", "", &err)
+	}
+
 	var ref = reflect.TypeOf(function)
 	if ref == nil || ref.Kind() != reflect.Func {
 		return errors.New("di: invalid function")
@@ -155,46 +382,121 @@ func (self *resolver) Call(function any, opts ...Option) error {
 	}
 
 	var options = newCallOptions(opts)
+	options.caller = caller
+
 	if options.returns != nil && ref.NumOut()-returnsAnError-len(options.returns) != 0 {
 		return fmt.Errorf("di: cannot assign %d returned values to %d receivers", ref.NumOut()-returnsAnError, len(options.returns))
 	}
 
-	var args, err = self.arguments(function)
-	if err != nil {
+	for i, ret := range options.returns {
+		if isPlaceholder(ret) {
+			continue
+		}
+
+		var t = reflect.TypeOf(ret)
+		if t.Kind() != reflect.Ptr || !reflect.ValueOf(ret).Elem().CanSet() {
+			return fmt.Errorf("di: cannot assign returned value of type %s to %s", typeName(ref.Out(i)), t.String())
+		}
+
+		if !ref.Out(i).AssignableTo(t.Elem()) {
+			return fmt.Errorf("di: cannot assign returned value of type %s to %s", typeName(ref.Out(i)), typeName(t.Elem()))
+		}
+	}
+
+	var args []reflect.Value
+	if args, err = self.arguments(function, options.optionalArgs, options.namedArgs); err != nil {
 		return err
 	}
 
-	var out = reflect.ValueOf(function).Call(args)
+	var out = callFunction(function, args)
 	// if there is something returned from a function and the last value is error and it's not nil then return it
 	if returnsAnError == 1 && !out[len(out)-1].IsNil() {
 		return out[len(out)-1].Interface().(error)
 	}
 
 	for i, ret := range options.returns {
-		var t = reflect.TypeOf(ret)
-		if t == nil || t.Kind() != reflect.Ptr || !reflect.ValueOf(ret).Elem().CanSet() || ref.Out(i) != t.Elem() {
-			return fmt.Errorf("di: cannot assign returned value of type %s to %s", ref.Out(i).Name(), t.Elem().Name())
+		if !isPlaceholder(ret) {
+			reflect.ValueOf(ret).Elem().Set(out[i])
 		}
+	}
 
-		reflect.ValueOf(ret).Elem().Set(out[i])
+	if options.bindResults {
+		return self.bindResults(ref, out[:len(out)-returnsAnError], options)
 	}
 
 	return nil
 }
 
+// typeName returns a name of a named type or a type literal otherwise
+func typeName(t reflect.Type) string {
+	if t.Name() != "" {
+		return t.Name()
+	}
+
+	return t.String()
+}
+
+// isPlaceholder checks whether a WithReturn() receiver is nil, so that a returned value is skipped
+func isPlaceholder(ret any) bool {
+	if ret == nil {
+		return true
+	}
+
+	var v = reflect.ValueOf(ret)
+
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// bindResults binds values returned by a called function as singletons into the first container
+func (self *resolver) bindResults(ref reflect.Type, out []reflect.Value, options callOptions) error {
+	if len(self.containers) == 0 {
+		return errors.New("di: no container to bind returned values to")
+	}
+
+	if len(out) == 0 {
+		return errors.New("di: the function must return useful values to bind")
+	}
+
+	var types = make([]reflect.Type, len(out))
+	for i := range out {
+		types[i] = ref.Out(i)
+	}
+
+	var constructor = reflect.MakeFunc(reflect.FuncOf(nil, types, false), func([]reflect.Value) []reflect.Value { return out }).Interface()
+
+	if c, ok := self.containers[0].(*container); ok {
+		return c.bind(constructor, bindOptions{names: options.resultNames, caller: options.caller})
+	}
+
+	var opts []Option
+	if len(options.resultNames) > 0 {
+		opts = append(opts, WithName(options.resultNames...))
+	}
+
+	return self.containers[0].Singleton(constructor, opts...)
+}
+
 // Resolve takes a receiver and fills it with the related implementation.
-func (self *resolver) Resolve(receiver any, opts ...Option) error {
+func (self *resolver) Resolve(receiver any, opts ...Option) (err error) {
 	var ref = reflect.TypeOf(receiver)
 	if ref == nil || ref.Kind() != reflect.Ptr {
 		return errors.New("di: invalid receiver")
 	}
 
-	var (
-		options   = newResolveOptions(opts)
-		inst, err = self.resolveBinding(ref.Elem(), options.name)
-	)
+	var options = newResolveOptions(opts)
+	if self.recovers() {
+		defer recoverPanic(ref, options.name, "", &err)
+	}
 
-	if err != nil {
+	var bnd Binding
+	if bnd, err = self.getBinding(ref.Elem(), options.name); err != nil {
+		// failed instantiation is reported by resolveBindingInstance
+		self.allObservers().notify(func(o Observer) { o.OnError(Event{Abstraction: ref.Elem(), Name: options.name, Err: err}) })
+		return err
+	}
+
+	var inst any
+	if inst, err = self.resolveBindingInstance(bnd); err != nil {
 		return err
 	}
 
@@ -205,7 +507,46 @@ func (self *resolver) Resolve(receiver any, opts ...Option) error {
 
 // Fill takes a struct and resolves the fields with the tag `di:"..."`.
 // Alternatively map[string]Type or []Type can be provided. It will be filled with all available implementations of provided Type.
-func (self *resolver) Fill(receiver any) (err error) {
+// WithSetters() option additionally calls setter methods of the receiver.
+func (self *resolver) Fill(receiver any, opts ...Option) error {
+	var (
+		options = newFillOptions(opts)
+		obs     = self.allObservers()
+	)
+
+	if len(obs) == 0 {
+		var _, err = self.fillWithSetters(receiver, options.setters)
+		return err
+	}
+
+	var start = time.Now()
+	var setters, err = self.fillWithSetters(receiver, options.setters)
+	var event = Event{Abstraction: reflect.TypeOf(receiver), Duration: time.Since(start), Setters: setters, Err: err}
+
+	if err != nil {
+		obs.notify(func(o Observer) { o.OnError(event) })
+		return err
+	}
+
+	obs.notify(func(o Observer) { o.OnFill(event) })
+
+	return nil
+}
+
+// fillWithSetters fills a receiver and calls its setters selected by the policy
+func (self *resolver) fillWithSetters(receiver any, policy *setterPolicy) (setters []string, err error) {
+	if err = self.fill(receiver); err != nil {
+		return
+	}
+
+	if self.recovers() {
+		defer recoverPanic(reflect.TypeOf(receiver), "", "", &err)
+	}
+
+	return self.injectSetters(receiver, policy)
+}
+
+func (self *resolver) fill(receiver any) (err error) {
 	var ref = reflect.TypeOf(receiver)
 	if ref == nil {
 		return errors.New("di: invalid receiver: nil")
@@ -217,10 +558,14 @@ func (self *resolver) Fill(receiver any) (err error) {
 
 	defer func() {
 		if err != nil {
-			err = fmt.Errorf("%s: filling %s", err.Error(), ref.String())
+			err = fmt.Errorf("%w: filling %s", err, ref.String())
 		}
 	}()
 
+	if self.recovers() {
+		defer recoverPanic(ref, "", "", &err)
+	}
+
 	switch ref.Elem().Kind() {
 	case reflect.Struct:
 		err = self.fillStruct(receiver)
@@ -243,7 +588,12 @@ func (self *resolver) Fill(receiver any) (err error) {
 }
 
 func (self *resolver) fillStruct(receiver any) error {
-	var elem = reflect.ValueOf(receiver).Elem()
+	var (
+		elem    = reflect.ValueOf(receiver).Elem()
+		config  []configField
+		secrets []secretField
+	)
+
 	for i := 0; i < elem.NumField(); i++ {
 		var tag, ok = elem.Type().Field(i).Tag.Lookup("di")
 		if !ok {
@@ -251,17 +601,32 @@ func (self *resolver) fillStruct(receiver any) error {
 		}
 
 		var canBeSkipped bool
-		tag, canBeSkipped = self.canBeSkipped(tag)
+		if tag, canBeSkipped = self.canBeSkipped(tag); strings.Contains(tag, omitemptySuffix) {
+			return fmt.Errorf("di: %v has an invalid struct tag: omitempty must be the last option", elem.Type().Field(i).Name)
+		}
+
+		if cfg, isConfig := parseConfigTag(tag); isConfig {
+			config = append(config, configField{index: i, tag: cfg, skippable: canBeSkipped})
+			continue
+		}
+
+		if secret, isSecret := parseSecretTag(tag); isSecret {
+			secrets = append(secrets, secretField{index: i, name: secret, skippable: canBeSkipped})
+			continue
+		}
 
 		var name string
-		switch tag {
-		case "type":
+		switch {
+		case tag == "type":
 			name = DefaultBindName
 
-		case "name":
+		case tag == "name":
 			name = elem.Type().Field(i).Name
 
-		case "recursive":
+		case strings.HasPrefix(tag, nameTagPrefix) && tag != nameTagPrefix:
+			name = strings.TrimPrefix(tag, nameTagPrefix)
+
+		case tag == "recursive":
 			var ptr = reflect.NewAt(elem.Field(i).Type(), unsafe.Pointer(elem.Field(i).UnsafeAddr())).Elem()
 
 			switch ptr.Kind() {
@@ -288,77 +653,144 @@ func (self *resolver) fillStruct(receiver any) error {
 			return err
 		}
 
-		var ptr = reflect.NewAt(elem.Field(i).Type(), unsafe.Pointer(elem.Field(i).UnsafeAddr())).Elem()
-		ptr.Set(reflect.ValueOf(instance))
+		setField(elem, i, reflect.ValueOf(instance))
 	}
 
-	return nil
+	if err := self.fillConfig(elem, config); err != nil {
+		return err
+	}
+
+	return self.fillSecrets(elem, secrets)
+}
+
+// setField sets a value to a struct field regardless of whether it's exported or not
+func setField(elem reflect.Value, i int, value reflect.Value) {
+	reflect.NewAt(elem.Field(i).Type(), unsafe.Pointer(elem.Field(i).UnsafeAddr())).Elem().Set(value)
 }
 
 func (self *resolver) fillSlice(receiver any) error {
 	var (
-		elem   = reflect.TypeOf(receiver).Elem()
-		result = reflect.MakeSlice(reflect.SliceOf(elem.Elem()), 0, 3)
+		elem       = reflect.TypeOf(receiver).Elem()
+		items, err = self.collect(elem.Elem(), false)
 	)
 
-	for _, cnt := range self.containers {
-		var bindings, err = cnt.ListBindings(elem.Elem())
-		if err != nil {
-			continue
-		}
+	if err != nil {
+		return err
+	}
 
-		for _, bnd := range bindings {
-			var instance any
-			if instance, err = self.resolveBindingInstance(bnd); err != nil {
-				return err
-			}
+	if len(items) == 0 {
+		return fmt.Errorf("di: no binding found for %v", elem.Elem().String())
+	}
 
-			result = reflect.Append(result, reflect.ValueOf(instance))
-		}
+	reflect.ValueOf(receiver).Elem().Set(collection(elem, items))
+
+	return nil
+}
+
+func (self *resolver) fillMap(receiver any) error {
+	var (
+		elem       = reflect.TypeOf(receiver).Elem()
+		items, err = self.collect(elem.Elem(), true)
+	)
+
+	if err != nil {
+		return err
 	}
 
-	if result.Len() == 0 {
+	if len(items) == 0 {
 		return fmt.Errorf("di: no binding found for %v", elem.Elem().String())
 	}
 
-	reflect.ValueOf(receiver).Elem().Set(result)
+	reflect.ValueOf(receiver).Elem().Set(collection(elem, items))
 
 	return nil
 }
 
-func (self *resolver) fillMap(receiver any) error {
+// collected is an instance of a binding gathered into a slice or a map
+type collected struct {
+	name     string
+	instance any
+}
+
+// collect resolves all bindings of an abstraction. Containers are walked in resolution order and bindings of a container
+// in declaration order, so collections are filled deterministically. If unique is set, a name bound in several containers
+// is taken from the last one, which keeps its position of the first one.
+func (self *resolver) collect(abstraction reflect.Type, unique bool) ([]collected, error) {
 	var (
-		elem   = reflect.TypeOf(receiver).Elem()
-		result = reflect.MakeMapWithSize(reflect.MapOf(elem.Key(), elem.Elem()), 3)
+		bindings []Binding
+		names    []string
+		index    = make(map[string]int)
 	)
 
 	for _, cnt := range self.containers {
-		var bindings, err = cnt.ListBindings(elem.Elem())
+		var list, err = cnt.ListBindings(abstraction)
 		if err != nil {
 			continue
 		}
 
-		for name, bnd := range bindings {
-			var instance any
-			if instance, err = self.resolveBindingInstance(bnd); err != nil {
-				return err
+		for _, e := range sortedBindings(map[reflect.Type]map[string]Binding{abstraction: list}) {
+			if i, ok := index[e.name]; unique && ok {
+				bindings[i] = e.binding
+				continue
 			}
 
-			result.SetMapIndex(reflect.ValueOf(name), reflect.ValueOf(instance))
+			index[e.name] = len(bindings)
+			bindings, names = append(bindings, e.binding), append(names, e.name)
 		}
 	}
 
-	if result.Len() == 0 {
-		return fmt.Errorf("di: no binding found for %v", elem.Elem().String())
+	var out = make([]collected, 0, len(bindings))
+	for i, bnd := range bindings {
+		var instance, err = self.resolveBindingInstance(bnd)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, collected{name: names[i], instance: instance})
 	}
 
-	reflect.ValueOf(receiver).Elem().Set(result)
+	return out, nil
+}
 
-	return nil
+// hasBindings checks whether any of containers has a binding of an abstraction
+func (self *resolver) hasBindings(abstraction reflect.Type) bool {
+	for _, cnt := range self.containers {
+		if list, err := cnt.ListBindings(abstraction); err == nil && len(list) > 0 {
+			return true
+		}
+	}
+
+	return false
 }
 
+// collection builds a slice or a map of provided type out of collected instances
+func collection(t reflect.Type, items []collected) reflect.Value {
+	if t.Kind() == reflect.Map {
+		var out = reflect.MakeMapWithSize(t, len(items))
+		for _, item := range items {
+			out.SetMapIndex(reflect.ValueOf(item.name), reflect.ValueOf(item.instance))
+		}
+
+		return out
+	}
+
+	var out = reflect.MakeSlice(t, 0, len(items))
+	for _, item := range items {
+		out = reflect.Append(out, reflect.ValueOf(item.instance))
+	}
+
+	return out
+}
+
+// isCollection checks whether an argument of provided type can be filled with all bindings of its element type
+func isCollection(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice || t.Kind() == reflect.Map && t.Key() == reflect.TypeOf("")
+}
+
+// omitemptySuffix marks a field that is left as is if it can't be resolved, it must be the last tag option
+const omitemptySuffix = ",omitempty"
+
 func (self *resolver) canBeSkipped(tag string) (string, bool) {
-	const omitemptySuffix = ",omitempty"
 	var isOmitempty bool
 
 	if strings.Contains(tag, omitemptySuffix) {
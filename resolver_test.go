@@ -36,6 +36,19 @@ func (suite *ResolverSuite) TestNewResolver() {
 	suite.Require().EqualError(di.Call(context.Background(), func(s Shape) { return }), "di: no binding found for di_test.Shape")
 }
 
+func (suite *ResolverSuite) TestContainers() {
+	var (
+		extra = di.NewContainer()
+		rsl   = di.NewResolver(suite.container, extra)
+		list  = rsl.Containers()
+	)
+
+	suite.Require().Equal([]di.Container{suite.container, extra}, list)
+
+	list[0] = nil
+	suite.Require().Equal(suite.container, rsl.Containers()[0])
+}
+
 func (suite *ResolverSuite) TestCallMulti() {
 	suite.Require().NoError(suite.container.Singleton(func() (Shape, Database, error) {
 		return &Rectangle{a: 777}, &MySQL{}, nil
@@ -63,6 +76,123 @@ func (suite *ResolverSuite) TestCallReturn() {
 	suite.Require().IsType(&MySQL{}, db)
 }
 
+func (suite *ResolverSuite) TestCallReturnAssignable() {
+	var (
+		db    Database
+		shape any
+		count int
+	)
+
+	suite.Require().NoError(suite.resolver.Call(func() (*MySQL, *Circle, int) {
+		return &MySQL{}, &Circle{}, 3
+	}, di.WithReturn(&db, nil, (*int)(nil))))
+
+	suite.Require().IsType(&MySQL{}, db)
+	suite.Require().Nil(shape)
+	suite.Require().Zero(count)
+
+	suite.Require().NoError(suite.resolver.Call(func() (*MySQL, *Circle) {
+		return &MySQL{}, &Circle{}
+	}, di.WithReturn(nil, &shape)))
+	suite.Require().IsType(&Circle{}, shape)
+
+	var called bool
+	suite.Require().EqualError(suite.resolver.Call(func() Database {
+		called = true
+		return &MySQL{}
+	}, di.WithReturn(new(*MySQL))), "di: cannot assign returned value of type Database to *di_test.MySQL")
+	suite.Require().False(called)
+
+	suite.Require().EqualError(suite.resolver.Call(func() Database { return &MySQL{} }, di.WithReturn(db)),
+		"di: cannot assign returned value of type Database to MySQL")
+}
+
+func (suite *ResolverSuite) TestCallBindResults() {
+	var db Database
+	suite.Require().NoError(suite.resolver.Call(func() (Database, Shape, error) {
+		return &MySQL{}, newCircle(), nil
+	}, di.WithReturn(&db, nil), di.WithBindResults()))
+
+	var bound Database
+	suite.Require().NoError(suite.resolver.Resolve(&bound))
+	suite.Require().Same(db, bound)
+
+	var shape Shape
+	suite.Require().NoError(suite.resolver.Resolve(&shape))
+
+	var list, _ = suite.container.ListBindings(reflect.TypeOf((*Shape)(nil)).Elem())
+	suite.Require().Len(list, 1)
+
+	var infos = suite.container.Bindings()
+	suite.Require().Contains(infos[0].Caller, "resolver_test.go")
+
+	suite.Require().NoError(suite.resolver.Call(func() Shape { return newRectangle() }, di.WithBindResults("a", "b")))
+	suite.Require().NoError(suite.resolver.Resolve(&shape, di.WithName("b")))
+	suite.Require().IsType(&Rectangle{}, shape)
+
+	suite.Require().EqualError(suite.resolver.Call(func() {}, di.WithBindResults()), "di: the function must return useful values to bind")
+	suite.Require().EqualError(di.NewResolver().Call(func() Shape { return newRectangle() }, di.WithBindResults()), "di: no container to bind returned values to")
+	suite.Require().EqualError(suite.resolver.Call(func() (Shape, error) { return nil, errors.New("dummy error") }, di.WithBindResults("c")), "dummy error")
+
+	list, _ = suite.container.ListBindings(reflect.TypeOf((*Shape)(nil)).Elem())
+	suite.Require().Len(list, 3)
+}
+
+func (suite *ResolverSuite) TestCallVariadic() {
+	var (
+		other = di.NewContainer()
+		rsl   = di.NewResolver(suite.container, other)
+	)
+
+	suite.Require().NoError(other.Singleton(newCircle, di.WithName("z")))
+	suite.Require().NoError(suite.container.Singleton(newRectangle, di.WithName("b")))
+	suite.Require().NoError(suite.container.Singleton(newCircle, di.WithName("a")))
+
+	var shapes []Shape
+	suite.Require().NoError(rsl.Call(func(list ...Shape) { shapes = list }))
+	suite.Require().Len(shapes, 3)
+	suite.Require().IsType(&Rectangle{}, shapes[0])
+	suite.Require().IsType(&Circle{}, shapes[1])
+	suite.Require().IsType(&Circle{}, shapes[2])
+
+	var dbs []Database
+	suite.Require().NoError(rsl.Call(func(s Shape, list ...Database) { dbs = list }, di.WithOptionalArgs()))
+	suite.Require().NotNil(dbs)
+	suite.Require().Empty(dbs)
+
+	// explicit binding of a slice takes precedence
+	suite.Require().NoError(suite.container.Singleton(func() []Shape { return []Shape{newCircle()} }))
+	suite.Require().NoError(rsl.Call(func(list ...Shape) { shapes = list }))
+	suite.Require().Len(shapes, 1)
+}
+
+func (suite *ResolverSuite) TestCallCollections() {
+	var (
+		other = di.NewContainer()
+		rsl   = di.NewResolver(suite.container, other)
+	)
+
+	suite.Require().EqualError(rsl.Call(func([]Shape) {}), "di: no binding found for []di_test.Shape")
+	suite.Require().NoError(rsl.Call(func([]Shape) {}, di.WithOptionalArgs()))
+
+	suite.Require().NoError(suite.container.Singleton(newCircle, di.WithName("a", "b")))
+	suite.Require().NoError(other.Singleton(newRectangle, di.WithName("b", "c")))
+
+	suite.Require().NoError(rsl.Call(func(list []Shape, dict map[string]Shape) {
+		suite.Require().Len(list, 4)
+		suite.Require().Len(dict, 3)
+
+		// names bound in several containers are taken from the last one, as Fill() does
+		suite.Require().IsType(&Rectangle{}, dict["b"])
+		suite.Require().IsType(&Circle{}, dict["a"])
+		suite.Require().IsType(&Rectangle{}, dict["c"])
+	}))
+
+	suite.Require().NoError(suite.container.Factory(func(list ...Shape) Database { return &MySQL{} }))
+	suite.Require().NoError(suite.container.Factory(func(list []Shape) Database { return &MySQL{} }, di.WithName("list")))
+	suite.Require().NoError(suite.container.Validate())
+}
+
 func (suite *ResolverSuite) TestCallWith() {
 	suite.Require().NoError(suite.container.Singleton(newCircle))
 
@@ -117,6 +247,34 @@ func (suite *ResolverSuite) TestCallReturnedError() {
 	suite.Require().EqualError(suite.resolver.Call(func(s Shape) (err error) { return errors.New("dummy error") }), "dummy error")
 }
 
+func (suite *ResolverSuite) TestCallOptional() {
+	suite.Require().NoError(suite.container.Singleton(newCircle))
+
+	suite.Require().NoError(suite.resolver.Call(func(s di.Optional[Shape], db di.Optional[Database]) {
+		suite.Require().True(s.Present)
+		suite.Require().IsType(&Circle{}, s.Value)
+		suite.Require().False(db.Present)
+		suite.Require().Nil(db.Value)
+	}))
+}
+
+func (suite *ResolverSuite) TestCallOptionalFactoryError() {
+	suite.Require().NoError(suite.container.Factory(func() (Shape, error) {
+		return nil, errors.New("factory error")
+	}))
+
+	suite.Require().EqualError(suite.resolver.Call(func(s di.Optional[Shape]) {}), "factory error")
+}
+
+func (suite *ResolverSuite) TestCallOptionalArgs() {
+	suite.Require().NoError(suite.container.Singleton(newCircle))
+
+	suite.Require().NoError(suite.resolver.Call(func(s Shape, db Database) {
+		suite.Require().IsType(&Circle{}, s)
+		suite.Require().Nil(db)
+	}, di.WithOptionalArgs()))
+}
+
 func (suite *ResolverSuite) TestResolve() {
 	suite.Require().NoError(suite.container.Singleton(newRectangle))
 	suite.Require().NoError(suite.container.Singleton(newMySQL))
@@ -263,6 +421,23 @@ func (suite *ResolverSuite) TestFillMap() {
 	suite.Require().IsType(&Rectangle{}, shapes["square"])
 }
 
+func (suite *ResolverSuite) TestFillMapContainers() {
+	var other = di.NewContainer()
+	suite.Require().NoError(suite.container.Singleton(newCircle, di.WithName("a", "b")))
+	suite.Require().NoError(other.Singleton(newRectangle, di.WithName("b", "c")))
+
+	// names bound in several containers are taken from the last one
+	var shapes map[string]Shape
+	suite.Require().NoError(di.NewResolver(suite.container, other).Fill(&shapes))
+	suite.Require().Len(shapes, 3)
+	suite.Require().IsType(&Circle{}, shapes["a"])
+	suite.Require().IsType(&Rectangle{}, shapes["b"])
+	suite.Require().IsType(&Rectangle{}, shapes["c"])
+
+	suite.Require().NoError(di.NewResolver(other, suite.container).Fill(&shapes))
+	suite.Require().IsType(&Circle{}, shapes["b"])
+}
+
 func (suite *ResolverSuite) TestFillStructWithSliceMap() {
 	suite.Require().NoError(suite.container.Singleton(newCircle, di.WithName("circle")))
 	suite.Require().NoError(suite.container.Singleton(newRectangle, di.WithName("square")))
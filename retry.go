@@ -0,0 +1,109 @@
+package di
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// RetryError is returned when all attempts of a constructor or a post-construct hook call fail
+type RetryError struct {
+	Attempts []error // errors of the attempts made
+	Err      error   // error retrying was stopped with: an error of the last attempt or a context error
+
+	stopped bool // retrying was cut off by the context before attempts were exhausted
+}
+
+// Error implements error interface
+func (e *RetryError) Error() string {
+	var list = make([]string, len(e.Attempts))
+	for i, err := range e.Attempts {
+		list[i] = fmt.Sprintf("attempt %d: %s", i+1, err.Error())
+	}
+
+	if !e.stopped {
+		return fmt.Sprintf("di: %d attempts failed: %s", len(e.Attempts), strings.Join(list, "; "))
+	}
+
+	return fmt.Sprintf("di: retrying stopped after %d attempts: %s: %s", len(e.Attempts), e.Err.Error(), strings.Join(list, "; "))
+}
+
+// Unwrap returns an error retrying was stopped with
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// retryPolicy describes how failed constructors are retried
+type retryPolicy struct {
+	attempts int
+	backoff  time.Duration
+}
+
+// maxBackoffShift keeps exponential backoff from overflowing
+const maxBackoffShift = 16
+
+// do calls fn until it succeeds, attempts are exhausted or context is done
+func (self retryPolicy) do(ctx context.Context, fn func() error) error {
+	if self.attempts <= 1 {
+		return fn()
+	}
+
+	var errs []error
+	for i := 0; i < self.attempts; i++ {
+		if i > 0 {
+			var timer = time.NewTimer(self.delay(i))
+			select {
+			case <-timer.C:
+
+			case <-ctx.Done():
+				timer.Stop()
+				return &RetryError{Attempts: errs, Err: ctx.Err(), stopped: true}
+			}
+		}
+
+		var err = fn()
+		if err == nil {
+			return nil
+		}
+
+		errs = append(errs, err)
+	}
+
+	return &RetryError{Attempts: errs, Err: errs[len(errs)-1]}
+}
+
+// delay returns a jittered exponential delay before the retry: a random duration between a half and a full backoff
+// doubled on every retry
+func (self retryPolicy) delay(retry int) time.Duration {
+	if self.backoff <= 0 {
+		return 0
+	}
+
+	var shift = retry - 1
+	if shift > maxBackoffShift {
+		shift = maxBackoffShift
+	}
+
+	var d = self.backoff << shift
+
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// context returns a context bound to resolver containers or context.Background() if there is none
+func (self *resolver) context() context.Context {
+	var bnd, err = self.getBinding(contextType, DefaultBindName)
+	if err != nil {
+		return context.Background()
+	}
+
+	if ctx, ok := bnd.instance.(context.Context); ok {
+		return ctx
+	}
+
+	return context.Background()
+}
+
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
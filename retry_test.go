@@ -0,0 +1,136 @@
+package di_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HnH/di"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestRetrySuite(t *testing.T) {
+	suite.Run(t, new(RetrySuite))
+}
+
+type RetrySuite struct {
+	suite.Suite
+}
+
+// flaky fails a number of calls before succeeding
+type flaky struct {
+	failures int
+	calls    int
+}
+
+func (f *flaky) call() error {
+	if f.calls++; f.calls <= f.failures {
+		return fmt.Errorf("not ready %d", f.calls)
+	}
+
+	return nil
+}
+
+// flakyConnection fails a number of Construct() calls
+type flakyConnection struct {
+	*flaky
+}
+
+func (c flakyConnection) Construct(context.Context) error {
+	return c.call()
+}
+
+func (suite *RetrySuite) TestSingleton() {
+	var (
+		c = di.NewContainer()
+		f = &flaky{failures: 2}
+	)
+
+	suite.Require().NoError(c.Singleton(func() (Shape, error) { return newCircle(), f.call() }, di.WithRetry(3, time.Millisecond)))
+	suite.Require().Equal(3, f.calls)
+
+	f = &flaky{failures: 3}
+	var err = c.Singleton(func() (Database, error) { return newMySQL(), f.call() }, di.WithRetry(3, time.Millisecond))
+
+	var retryErr *di.RetryError
+	suite.Require().True(errors.As(err, &retryErr))
+	suite.Require().Len(retryErr.Attempts, 3)
+	suite.Require().EqualError(err, "di: 3 attempts failed: attempt 1: not ready 1; attempt 2: not ready 2; attempt 3: not ready 3")
+	suite.Require().Equal(retryErr.Attempts[2], errors.Unwrap(err))
+
+	// without retrying the first error is returned as is
+	f = &flaky{failures: 1}
+	suite.Require().EqualError(c.Singleton(func() (Database, error) { return newMySQL(), f.call() }), "not ready 1")
+}
+
+// multiError is a slice-based error which dynamic type is not comparable
+type multiError []error
+
+func (m multiError) Error() string {
+	var list = make([]string, len(m))
+	for i, err := range m {
+		list[i] = err.Error()
+	}
+
+	return strings.Join(list, ", ")
+}
+
+func (suite *RetrySuite) TestUncomparableError() {
+	var (
+		c     = di.NewContainer()
+		calls int
+	)
+
+	var err = c.Singleton(func() (Shape, error) {
+		calls++
+		return nil, multiError{fmt.Errorf("not ready %d", calls), errors.New("no route")}
+	}, di.WithRetry(2, 0))
+
+	suite.Require().EqualError(err, "di: 2 attempts failed: attempt 1: not ready 1, no route; attempt 2: not ready 2, no route")
+	suite.Require().Equal(2, calls)
+}
+
+func (suite *RetrySuite) TestConstruct() {
+	var (
+		c = di.NewContainer()
+		f = &flaky{failures: 1}
+	)
+
+	suite.Require().NoError(c.Singleton(context.Background))
+	suite.Require().NoError(c.Singleton(func() flakyConnection { return flakyConnection{f} }, di.WithRetry(2, 0)))
+	suite.Require().Equal(2, f.calls)
+}
+
+func (suite *RetrySuite) TestFactory() {
+	var (
+		c = di.NewContainer()
+		r = di.NewResolver(c)
+		f = &flaky{failures: 2}
+	)
+
+	suite.Require().NoError(c.Factory(func() (Shape, error) { return newCircle(), f.call() }, di.WithRetry(3, time.Millisecond)))
+	suite.Require().Equal(0, f.calls)
+
+	var s Shape
+	suite.Require().NoError(r.Resolve(&s))
+	suite.Require().Equal(3, f.calls)
+}
+
+func (suite *RetrySuite) TestCancel() {
+	var (
+		c           = di.NewContainer()
+		f           = &flaky{failures: 5}
+		ctx, cancel = context.WithCancel(context.Background())
+	)
+
+	cancel()
+	suite.Require().NoError(c.Singleton(func() context.Context { return ctx }))
+
+	var err = c.Singleton(func() (Shape, error) { return newCircle(), f.call() }, di.WithRetry(5, time.Hour))
+	suite.Require().ErrorIs(err, context.Canceled)
+	suite.Require().EqualError(err, "di: retrying stopped after 1 attempts: context canceled: attempt 1: not ready 1")
+	suite.Require().Equal(1, f.calls)
+}
@@ -0,0 +1,233 @@
+package di
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// Secret holds a sensitive value which is redacted whenever it's printed, formatted or marshalled.
+// The value is kept behind a pointer, so it's not exposed even when Secret is an unexported field of a printed struct.
+type Secret struct {
+	value *string
+}
+
+const secretRedacted = "[REDACTED]"
+
+// NewSecret wraps a sensitive value into a Secret
+func NewSecret(value string) Secret {
+	return Secret{value: &value}
+}
+
+// Value returns a raw sensitive value
+func (self Secret) Value() string {
+	if self.value == nil {
+		return ""
+	}
+
+	return *self.value
+}
+
+// String implements fmt.Stringer interface
+func (self Secret) String() string {
+	return secretRedacted
+}
+
+// GoString implements fmt.GoStringer interface
+func (self Secret) GoString() string {
+	return secretRedacted
+}
+
+// Format implements fmt.Formatter interface
+func (self Secret) Format(f fmt.State, _ rune) {
+	_, _ = f.Write([]byte(secretRedacted))
+}
+
+// MarshalText implements encoding.TextMarshaler interface
+func (self Secret) MarshalText() ([]byte, error) {
+	return []byte(secretRedacted), nil
+}
+
+// ErrSecretNotFound is returned from SecretSource when requested secret does not exist
+var ErrSecretNotFound = errors.New("di: secret not found")
+
+// SecretSource is an abstraction of an entity that provides sensitive values by their names.
+// Implementations must never put secret values into returned errors.
+type SecretSource interface {
+	Lookup(name string) (Secret, error)
+}
+
+// Secrets combines several sources into one. Sources are queried in provided order and the first found secret wins.
+// Returned SecretSource should be bound into a container to be used for `di:"secret=..."` struct tags.
+func Secrets(sources ...SecretSource) SecretSource {
+	return secretSources(sources)
+}
+
+type secretSources []SecretSource
+
+// Lookup implements SecretSource interface
+func (self secretSources) Lookup(name string) (Secret, error) {
+	for _, src := range self {
+		var secret, err = src.Lookup(name)
+		if errors.Is(err, ErrSecretNotFound) {
+			continue
+		}
+
+		return secret, err
+	}
+
+	return Secret{}, ErrSecretNotFound
+}
+
+// FileSecretSource returns a SecretSource that reads secrets from files in provided directory, e.g. `/run/secrets`.
+// Trailing newlines are trimmed from file contents.
+func FileSecretSource(dir string) SecretSource {
+	return fileSecretSource(dir)
+}
+
+type fileSecretSource string
+
+// Lookup implements SecretSource interface
+func (self fileSecretSource) Lookup(name string) (Secret, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return Secret{}, fmt.Errorf("di: invalid secret name %q", name)
+	}
+
+	var raw, err = os.ReadFile(filepath.Join(string(self), name))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return Secret{}, ErrSecretNotFound
+
+	case err != nil:
+		return Secret{}, fmt.Errorf("di: unable to read secret %s: %w", name, err)
+	}
+
+	return NewSecret(strings.TrimRight(string(raw), "\r\n")), nil
+}
+
+// EnvSecretSource returns a SecretSource that reads environment variables.
+// Names are converted the same way as in EnvSource, e.g. `db_password` with prefix `APP` becomes `APP_DB_PASSWORD`.
+func EnvSecretSource(prefix string) SecretSource {
+	return envSecretSource(prefix)
+}
+
+type envSecretSource string
+
+// Lookup implements SecretSource interface
+func (self envSecretSource) Lookup(name string) (Secret, error) {
+	if val, ok := os.LookupEnv(envName(string(self), name)); ok {
+		return NewSecret(val), nil
+	}
+
+	return Secret{}, ErrSecretNotFound
+}
+
+// MapSecretSource returns a SecretSource backed by an in-memory map. It's meant to be a stand-in for tests and local development.
+func MapSecretSource(values map[string]string) SecretSource {
+	return mapSecretSource(values)
+}
+
+type mapSecretSource map[string]string
+
+// Lookup implements SecretSource interface
+func (self mapSecretSource) Lookup(name string) (Secret, error) {
+	if val, ok := self[name]; ok {
+		return NewSecret(val), nil
+	}
+
+	return Secret{}, ErrSecretNotFound
+}
+
+const secretTagPrefix = "secret="
+
+// secretField is a struct field that has to be filled with a secret value
+type secretField struct {
+	index     int
+	name      string
+	skippable bool
+}
+
+func parseSecretTag(tag string) (string, bool) {
+	if !strings.HasPrefix(tag, secretTagPrefix) {
+		return "", false
+	}
+
+	var name = strings.TrimPrefix(tag, secretTagPrefix)
+
+	return name, name != ""
+}
+
+var (
+	secretType       = reflect.TypeOf(Secret{})
+	secretSourceType = reflect.TypeOf((*SecretSource)(nil)).Elem()
+)
+
+// fillSecrets resolves values for secret tagged fields from a bound SecretSource.
+// If no source is bound, fields that can be skipped are left as is. Supported field types are Secret, string and []byte. Missing required secrets are aggregated into a single error.
+func (self *resolver) fillSecrets(elem reflect.Value, fields []secretField) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	var src SecretSource
+	if _, err := self.getBinding(secretSourceType, DefaultBindName); err == nil || requiresSecrets(fields) {
+		if err = self.Resolve(&src); err != nil {
+			return err
+		}
+	}
+
+	var missing []string
+	for _, field := range fields {
+		var secret, err = Secret{}, ErrSecretNotFound
+		if src != nil {
+			secret, err = src.Lookup(field.name)
+		}
+
+		switch {
+		case errors.Is(err, ErrSecretNotFound) && field.skippable:
+			continue
+
+		case errors.Is(err, ErrSecretNotFound):
+			missing = append(missing, field.name)
+			continue
+
+		case err != nil:
+			return err
+		}
+
+		var t = elem.Field(field.index).Type()
+		switch {
+		case t == secretType:
+			setField(elem, field.index, reflect.ValueOf(secret))
+
+		case t.Kind() == reflect.String:
+			setField(elem, field.index, reflect.ValueOf(secret.Value()).Convert(t))
+
+		case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+			setField(elem, field.index, reflect.ValueOf([]byte(secret.Value())).Convert(t))
+
+		default:
+			return fmt.Errorf("di: unsupported type %s for secret %s", t.String(), field.name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("di: missing required secret(s): %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// requiresSecrets checks whether any of the fields can't be skipped
+func requiresSecrets(fields []secretField) bool {
+	for _, field := range fields {
+		if !field.skippable {
+			return true
+		}
+	}
+
+	return false
+}
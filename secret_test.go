@@ -0,0 +1,202 @@
+package di_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/HnH/di"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSecretSuite(t *testing.T) {
+	suite.Run(t, new(SecretSuite))
+}
+
+type SecretSuite struct {
+	container di.Container
+	resolver  di.Resolver
+
+	suite.Suite
+}
+
+func (suite *SecretSuite) SetupSuite() {
+	suite.container = di.NewContainer()
+	suite.resolver = di.NewResolver(suite.container)
+}
+
+func (suite *SecretSuite) TearDownTest() {
+	suite.container.Reset()
+}
+
+func (suite *SecretSuite) bindSecrets(sources ...di.SecretSource) {
+	suite.Require().NoError(suite.container.Singleton(func() di.SecretSource {
+		return di.Secrets(sources...)
+	}))
+}
+
+func (suite *SecretSuite) TestRedaction() {
+	var secret = di.NewSecret("hunter2")
+	suite.Require().Equal("hunter2", secret.Value())
+	suite.Require().Equal("", di.Secret{}.Value())
+
+	var wrapper = struct {
+		Exported   di.Secret
+		unexported di.Secret
+	}{secret, secret}
+
+	for _, out := range []string{
+		secret.String(),
+		fmt.Sprint(secret),
+		fmt.Sprintf("%s %v %q %x %d", secret, secret, secret, secret, secret),
+		fmt.Sprintf("%+v %#v", secret, secret),
+		fmt.Sprintf("%v %+v %#v", wrapper, wrapper, wrapper),
+		fmt.Errorf("wrapped: %v", secret).Error(),
+	} {
+		suite.Require().NotContains(out, "hunter2")
+	}
+
+	var raw, err = json.Marshal(wrapper)
+	suite.Require().NoError(err)
+	suite.Require().Equal(`{"Exported":"[REDACTED]"}`, string(raw))
+}
+
+func (suite *SecretSuite) TestFill() {
+	suite.bindSecrets(di.MapSecretSource(map[string]string{
+		"db_password": "hunter2",
+		"api_key":     "key",
+		"tls_key":     "pem",
+	}))
+
+	var target = struct {
+		Password di.Secret `di:"secret=db_password"`
+		APIKey   string    `di:"secret=api_key"`
+		TLSKey   []byte    `di:"secret=tls_key"`
+		Optional di.Secret `di:"secret=optional,omitempty"`
+	}{}
+
+	suite.Require().NoError(suite.resolver.Fill(&target))
+	suite.Require().Equal("hunter2", target.Password.Value())
+	suite.Require().Equal("key", target.APIKey)
+	suite.Require().Equal([]byte("pem"), target.TLSKey)
+	suite.Require().Equal("", target.Optional.Value())
+}
+
+func (suite *SecretSuite) TestFillMissing() {
+	suite.bindSecrets(di.MapSecretSource(map[string]string{}))
+
+	var target = struct {
+		Password di.Secret `di:"secret=db_password"`
+		APIKey   string    `di:"secret=api_key"`
+	}{}
+
+	var err = suite.resolver.Fill(&target)
+	suite.Require().Error(err)
+	suite.Require().True(strings.HasPrefix(err.Error(), "di: missing required secret(s): db_password, api_key: filling"))
+}
+
+func (suite *SecretSuite) TestFillUnsupportedType() {
+	suite.bindSecrets(di.MapSecretSource(map[string]string{"port": "hunter2"}))
+
+	var target = struct {
+		Port int `di:"secret=port"`
+	}{}
+
+	var err = suite.resolver.Fill(&target)
+	suite.Require().Error(err)
+	suite.Require().Contains(err.Error(), "di: unsupported type int for secret port")
+	suite.Require().NotContains(err.Error(), "hunter2")
+}
+
+func (suite *SecretSuite) TestFillUnbound() {
+	var target = struct {
+		Password di.Secret `di:"secret=db_password"`
+	}{}
+
+	var err = suite.resolver.Fill(&target)
+	suite.Require().Error(err)
+	suite.Require().Contains(err.Error(), "di: no binding found for di.SecretSource")
+
+	var optional = struct {
+		Password di.Secret `di:"secret=db_password,omitempty"`
+		Token    string    `di:"secret=token,omitempty"`
+	}{Token: "preset"}
+
+	suite.Require().NoError(suite.resolver.Fill(&optional))
+	suite.Require().Equal("", optional.Password.Value())
+	suite.Require().Equal("preset", optional.Token)
+}
+
+func (suite *SecretSuite) TestFileSecretSource() {
+	var dir = suite.T().TempDir()
+	suite.Require().NoError(os.WriteFile(filepath.Join(dir, "db_password"), []byte("hunter2\n"), 0o600))
+	suite.Require().NoError(os.Mkdir(filepath.Join(dir, "unreadable"), 0o700))
+
+	var src = di.FileSecretSource(dir)
+
+	var secret, err = src.Lookup("db_password")
+	suite.Require().NoError(err)
+	suite.Require().Equal("hunter2", secret.Value())
+
+	_, err = src.Lookup("missing")
+	suite.Require().ErrorIs(err, di.ErrSecretNotFound)
+
+	_, err = src.Lookup("../db_password")
+	suite.Require().EqualError(err, `di: invalid secret name "../db_password"`)
+
+	_, err = src.Lookup("unreadable")
+	suite.Require().Error(err)
+	suite.Require().True(strings.HasPrefix(err.Error(), "di: unable to read secret unreadable"))
+
+	suite.bindSecrets(src)
+
+	var target = struct {
+		Unreadable string `di:"secret=unreadable"`
+	}{}
+
+	suite.Require().Error(suite.resolver.Fill(&target))
+}
+
+func (suite *SecretSuite) TestEnvSecretSource() {
+	suite.T().Setenv("APP_DB_PASSWORD", "hunter2")
+
+	var secret, err = di.EnvSecretSource("app").Lookup("db_password")
+	suite.Require().NoError(err)
+	suite.Require().Equal("hunter2", secret.Value())
+
+	_, err = di.EnvSecretSource("app").Lookup("api_key")
+	suite.Require().ErrorIs(err, di.ErrSecretNotFound)
+}
+
+func (suite *SecretSuite) TestPrecedence() {
+	var src = di.Secrets(
+		di.MapSecretSource(map[string]string{"a": "first"}),
+		di.MapSecretSource(map[string]string{"a": "second", "b": "second"}),
+	)
+
+	var secret, err = src.Lookup("a")
+	suite.Require().NoError(err)
+	suite.Require().Equal("first", secret.Value())
+
+	secret, err = src.Lookup("b")
+	suite.Require().NoError(err)
+	suite.Require().Equal("second", secret.Value())
+
+	_, err = src.Lookup("c")
+	suite.Require().ErrorIs(err, di.ErrSecretNotFound)
+}
+
+func (suite *SecretSuite) TestVisualize() {
+	var ctx = di.Ctx(context.Background()).SetContainer(suite.container)
+
+	suite.Require().NoError(suite.container.Implementation(di.NewSecret("hunter2"), di.WithName("db_password")))
+	suite.Require().NoError(suite.container.Singleton(func() di.Secret { return di.NewSecret("hunter3") }))
+
+	for _, line := range ctx.Visualize() {
+		suite.Require().NotContains(line, "hunter")
+	}
+}
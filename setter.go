@@ -0,0 +1,132 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+)
+
+// DefaultSetterPattern matches methods called by WithSetters() when no methods or patterns are provided
+const DefaultSetterPattern = `Set[A-Z]\w*`
+
+// setterPolicy selects methods of an instance that are called to inject dependencies
+type setterPolicy struct {
+	methods  []string         // methods and patterns the policy was created with
+	names    []string         // methods that have to be called, an error is returned if one of them can't be
+	patterns []*regexp.Regexp // methods that are called if their arguments can be resolved
+	err      error            // invalid pattern error reported on binding or filling
+}
+
+// newSetterPolicy sorts provided methods into exact method names and regular expressions matching whole method names
+func newSetterPolicy(methods []string) *setterPolicy {
+	var out = &setterPolicy{methods: methods}
+	if len(methods) == 0 {
+		methods = []string{DefaultSetterPattern}
+	}
+
+	for _, m := range methods {
+		if regexp.QuoteMeta(m) == m {
+			out.names = append(out.names, m)
+			continue
+		}
+
+		var re, err = regexp.Compile("^(?:" + m + ")$")
+		if err != nil {
+			out.err = fmt.Errorf("di: invalid setter pattern %q: %w", m, err)
+			return out
+		}
+
+		out.patterns = append(out.patterns, re)
+	}
+
+	return out
+}
+
+// required checks whether a method was listed by its name
+func (self *setterPolicy) required(name string) bool {
+	for _, n := range self.names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matches checks whether a method name matches one of the patterns
+func (self *setterPolicy) matches(name string) bool {
+	for _, re := range self.patterns {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// injectSetters calls exported methods of an instance selected by the policy in alphabetical order and returns their names.
+// Methods matched by a pattern are skipped if some of their arguments can't be resolved, methods listed by name are required.
+func (self *resolver) injectSetters(instance any, policy *setterPolicy) ([]string, error) {
+	if policy == nil {
+		return nil, nil
+	}
+
+	if policy.err != nil {
+		return nil, policy.err
+	}
+
+	var (
+		v       = reflect.ValueOf(instance)
+		invoked []string
+	)
+
+	if !v.IsValid() {
+		return nil, nil
+	}
+
+	for _, name := range policy.names {
+		if _, ok := v.Type().MethodByName(name); !ok {
+			return nil, fmt.Errorf("di: %s has no setter %s", v.Type().String(), name)
+		}
+	}
+
+	for i := 0; i < v.NumMethod(); i++ {
+		var (
+			name     = v.Type().Method(i).Name
+			method   = v.Method(i)
+			required = policy.required(name)
+		)
+
+		if !required && (!policy.matches(name) || method.Type().NumIn() == 0 || !self.resolvable(method.Type())) {
+			continue
+		}
+
+		if _, err := self.invoke(method.Interface(), false, nil); err != nil {
+			return nil, fmt.Errorf("di: unable to call setter %s.%s: %w", v.Type().String(), name, err)
+		}
+
+		invoked = append(invoked, name)
+	}
+
+	return invoked, nil
+}
+
+// resolvable checks whether all arguments of a function can be resolved
+func (self *resolver) resolvable(function reflect.Type) bool {
+	for i := 0; i < function.NumIn(); i++ {
+		var t = function.In(i)
+		if isOptional(t) || function.IsVariadic() && i == function.NumIn()-1 {
+			continue
+		}
+
+		if isCollection(t) && self.hasBindings(t.Elem()) {
+			continue
+		}
+
+		if _, err := self.getBinding(t, DefaultBindName); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
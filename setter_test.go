@@ -0,0 +1,163 @@
+package di_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HnH/di"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSetterSuite(t *testing.T) {
+	suite.Run(t, new(SetterSuite))
+}
+
+type SetterSuite struct {
+	suite.Suite
+}
+
+// legacyClient is a third-party type that can't carry `di` tags
+type legacyClient struct {
+	shape   Shape
+	db      Database
+	timeout time.Duration
+	used    Database
+	err     error
+}
+
+func (c *legacyClient) SetShape(s Shape) {
+	c.shape = s
+}
+
+func (c *legacyClient) SetDatabase(db Database) error {
+	c.db = db
+	return c.err
+}
+
+func (c *legacyClient) SetTimeout(d time.Duration) {
+	c.timeout = d
+}
+
+func (c *legacyClient) UseDatabase(db Database) {
+	c.used = db
+}
+
+func (c *legacyClient) Settings() string {
+	return "settings"
+}
+
+// constructRecorder records setters called on constructed instances
+type constructRecorder struct {
+	di.NopObserver
+	setters [][]string
+}
+
+func (r *constructRecorder) OnConstruct(e di.Event) {
+	r.setters = append(r.setters, e.Setters)
+}
+
+func (suite *SetterSuite) container() di.Container {
+	var c = di.NewContainer()
+	suite.Require().NoError(c.Singleton(newCircle))
+	suite.Require().NoError(c.Singleton(newMySQL))
+
+	return c
+}
+
+func (suite *SetterSuite) TestSingleton() {
+	var c = suite.container()
+	suite.Require().NoError(c.Singleton(func() *legacyClient { return new(legacyClient) }, di.WithSetters()))
+
+	var client *legacyClient
+	suite.Require().NoError(di.NewResolver(c).Resolve(&client))
+	suite.Require().IsType(&Circle{}, client.shape)
+	suite.Require().IsType(&MySQL{}, client.db)
+	suite.Require().Nil(client.used)
+	suite.Require().Zero(client.timeout) // time.Duration is not bound, setter is skipped
+
+	var infos = c.Bindings()
+	suite.Require().Equal([]string{"SetDatabase", "SetShape"}, infos[len(infos)-1].Setters)
+	suite.Require().Nil(infos[0].Setters)
+}
+
+func (suite *SetterSuite) TestDisabled() {
+	var c = suite.container()
+	suite.Require().NoError(c.Singleton(func() *legacyClient { return new(legacyClient) }))
+
+	var client *legacyClient
+	suite.Require().NoError(di.NewResolver(c).Resolve(&client))
+	suite.Require().Nil(client.shape)
+	suite.Require().Nil(client.db)
+}
+
+func (suite *SetterSuite) TestMethods() {
+	var c = suite.container()
+	suite.Require().NoError(c.Singleton(func() *legacyClient { return new(legacyClient) }, di.WithSetters("UseDatabase", "SetS.*")))
+
+	var client *legacyClient
+	suite.Require().NoError(di.NewResolver(c).Resolve(&client))
+	suite.Require().IsType(&MySQL{}, client.used)
+	suite.Require().IsType(&Circle{}, client.shape)
+	suite.Require().Nil(client.db)
+}
+
+func (suite *SetterSuite) TestErrors() {
+	var c = suite.container()
+
+	suite.Require().EqualError(
+		c.Singleton(func() *legacyClient { return new(legacyClient) }, di.WithSetters("SetTimeout")),
+		"di: unable to call setter *di_test.legacyClient.SetTimeout: di: no binding found for time.Duration",
+	)
+
+	suite.Require().EqualError(
+		c.Singleton(func() *legacyClient { return new(legacyClient) }, di.WithSetters("SetLogger")),
+		"di: *di_test.legacyClient has no setter SetLogger",
+	)
+
+	suite.Require().EqualError(
+		c.Singleton(func() *legacyClient { return new(legacyClient) }, di.WithSetters("Set(")),
+		"di: invalid setter pattern \"Set(\": error parsing regexp: missing closing ): `^(?:Set()$`",
+	)
+
+	var errSetter = errors.New("connection refused")
+	var err = c.Singleton(func() *legacyClient { return &legacyClient{err: errSetter} }, di.WithSetters())
+	suite.Require().ErrorIs(err, errSetter)
+	suite.Require().EqualError(err, "di: unable to call setter *di_test.legacyClient.SetDatabase: connection refused")
+}
+
+func (suite *SetterSuite) TestFactory() {
+	var (
+		rec = new(constructRecorder)
+		c   = di.NewContainer(di.WithObserver(rec))
+	)
+
+	suite.Require().NoError(c.Singleton(newCircle))
+	suite.Require().NoError(c.Factory(func() *legacyClient { return new(legacyClient) }, di.WithSetters()))
+
+	var client *legacyClient
+	suite.Require().NoError(di.NewResolver(c).Resolve(&client))
+	suite.Require().IsType(&Circle{}, client.shape)
+	suite.Require().Nil(client.db) // Database is not bound, setter is skipped
+	suite.Require().Equal([][]string{nil, {"SetShape"}}, rec.setters)
+}
+
+func (suite *SetterSuite) TestFill() {
+	var (
+		rec    = new(eventRecorder)
+		r      = di.NewResolver(suite.container()).Observe(rec)
+		client legacyClient
+	)
+
+	suite.Require().NoError(r.Fill(&client, di.WithSetters("UseDatabase")))
+	suite.Require().IsType(&MySQL{}, client.used)
+	suite.Require().Nil(client.shape)
+	suite.Require().Equal([]string{"UseDatabase"}, rec.last.Setters)
+
+	client = legacyClient{}
+	suite.Require().NoError(r.Fill(&client))
+	suite.Require().Nil(client.used)
+	suite.Require().Nil(rec.last.Setters)
+
+	suite.Require().EqualError(r.Fill(&client, di.WithSetters("Set(")), "di: invalid setter pattern \"Set(\": error parsing regexp: missing closing ): `^(?:Set()$`")
+}
@@ -0,0 +1,57 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// TimeoutError is returned when construction of a binding takes longer than its timeout
+type TimeoutError struct {
+	Abstraction reflect.Type  // abstraction of the binding, the first one for constructors returning multiple values
+	Name        string        // name of the binding
+	Caller      string        // location the binding was declared at
+	Timeout     time.Duration // timeout that was exceeded
+}
+
+// Error implements error interface
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("di: constructing %s [%s] declared at %s timed out after %s", e.Abstraction.String(), e.Name, e.Caller, e.Timeout)
+}
+
+// Unwrap returns context.DeadlineExceeded
+func (e *TimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// guard calls fn constructing a binding, limits its execution time and recovers panics if it's enabled.
+// Constructors and post-construct hook calls made by a resolver passed to fn receive a context with a deadline.
+// A constructor that doesn't return keeps running in background after the timeout.
+func (self *resolver) guard(abstraction reflect.Type, name, caller string, timeout time.Duration, fn func(rsl *resolver) error) error {
+	var call = func(rsl *resolver) (err error) {
+		if rsl.recovers() {
+			defer recoverPanic(abstraction, name, caller, &err)
+		}
+
+		return fn(rsl)
+	}
+
+	if timeout <= 0 {
+		return call(self)
+	}
+
+	var err = runHook(self.context(), timeout, func(ctx context.Context) error {
+		var rsl = *self
+		rsl.ctx = ctx
+
+		return call(&rsl)
+	})
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &TimeoutError{Abstraction: abstraction, Name: name, Caller: caller, Timeout: timeout}
+	}
+
+	return err
+}
@@ -0,0 +1,103 @@
+package di_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/HnH/di"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestTimeoutSuite(t *testing.T) {
+	suite.Run(t, new(TimeoutSuite))
+}
+
+type TimeoutSuite struct {
+	release chan struct{}
+
+	suite.Suite
+}
+
+func (suite *TimeoutSuite) SetupTest() {
+	suite.release = make(chan struct{})
+}
+
+func (suite *TimeoutSuite) TearDownTest() {
+	close(suite.release)
+}
+
+// deadlineRecorder records whether Construct() received a context with a deadline
+type deadlineRecorder struct {
+	deadline bool
+}
+
+func (d *deadlineRecorder) Construct(ctx context.Context) error {
+	_, d.deadline = ctx.Deadline()
+	return nil
+}
+
+func (suite *TimeoutSuite) TestSingleton() {
+	var (
+		c       = di.NewContainer()
+		release = suite.release
+	)
+
+	var err = c.Singleton(func() Shape { <-release; return newCircle() }, di.WithName("hanging"), di.WithTimeout(10*time.Millisecond))
+	suite.Require().ErrorIs(err, context.DeadlineExceeded)
+
+	var timeoutErr *di.TimeoutError
+	suite.Require().True(errors.As(err, &timeoutErr))
+	suite.Require().Equal(reflect.TypeOf((*Shape)(nil)).Elem(), timeoutErr.Abstraction)
+	suite.Require().Equal("hanging", timeoutErr.Name)
+	suite.Require().Contains(timeoutErr.Caller, "timeout_test.go")
+	suite.Require().Equal(10*time.Millisecond, timeoutErr.Timeout)
+	suite.Require().Regexp(`^di: constructing di_test.Shape \[hanging\] declared at .+timeout_test.go:\d+ timed out after 10ms$`, err.Error())
+
+	var list, _ = c.ListBindings(reflect.TypeOf((*Shape)(nil)).Elem())
+	suite.Require().Empty(list)
+}
+
+func (suite *TimeoutSuite) TestContext() {
+	var (
+		c        = di.NewContainer()
+		recorder = new(deadlineRecorder)
+		received context.Context
+	)
+
+	suite.Require().NoError(c.Singleton(func(ctx context.Context) *deadlineRecorder { received = ctx; return recorder }, di.WithTimeout(time.Second)))
+	suite.Require().True(recorder.deadline)
+
+	var _, ok = received.Deadline()
+	suite.Require().True(ok)
+
+	// the context is not bound to the container
+	suite.Require().Error(di.NewResolver(c).Call(func(context.Context) {}))
+}
+
+func (suite *TimeoutSuite) TestFactory() {
+	var (
+		c       = di.NewContainer()
+		r       = di.NewResolver(c)
+		release = suite.release
+	)
+
+	suite.Require().NoError(c.Factory(func() Shape { <-release; return newCircle() }))
+	suite.Require().NoError(c.Factory(func() Database { time.Sleep(20 * time.Millisecond); return newMySQL() }, di.WithTimeout(time.Second)))
+
+	var s Shape
+	var err = r.Timeout(10 * time.Millisecond).Resolve(&s)
+	suite.Require().ErrorIs(err, context.DeadlineExceeded)
+	suite.Require().Regexp(`^di: constructing di_test.Shape \[default\] declared at .+timeout_test.go:\d+ timed out after 10ms$`, err.Error())
+
+	// binding timeout takes precedence over the resolver one
+	var db Database
+	suite.Require().NoError(r.Timeout(time.Millisecond).Resolve(&db))
+
+	var recorder *deadlineRecorder
+	suite.Require().NoError(c.Factory(func() *deadlineRecorder { return new(deadlineRecorder) }))
+	suite.Require().NoError(r.Timeout(time.Second).Resolve(&recorder))
+	suite.Require().True(recorder.deadline)
+}